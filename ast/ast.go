@@ -13,3 +13,8 @@ type Expr interface {
 	Node
 	expr()
 }
+
+type Pat interface {
+	Node
+	pattern()
+}
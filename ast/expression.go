@@ -11,6 +11,20 @@ type Expression struct{}
 func (e Expression) expr() {}
 func (e Expression) node() {}
 
+// IsAssignable report whether expr denote a place a value can be stored
+// in, that is a variable, an index `a[i]` or a field `a.b`
+func IsAssignable(expr Expr) bool {
+	switch node := expr.(type) {
+	case *NodeIdentifier, *NodeIndexExpr:
+		return true
+
+	case *NodeBinaryExpr:
+		return node.Operator == "."
+	}
+
+	return false
+}
+
 // #######################################################
 // ################### Node Boolean ######################😀
 // #######################################################
@@ -20,6 +34,14 @@ type NodeBoolean struct {
 	Value bool
 }
 
+// #######################################################
+// ###################### Node Null ######################😀
+// #######################################################
+type NodeNull struct {
+	Expression
+	Token token.Token
+}
+
 // #######################################################
 // ##################### Node Integer ####################😀
 // #######################################################
@@ -57,6 +79,17 @@ type NodeIndexExpr struct {
 	Index      Expr
 }
 
+// #######################################################
+// #################### Node Slice Expr ##################😀
+// #######################################################
+type NodeSliceExpr struct {
+	Expression
+	Token      token.Token
+	Identifier Expr
+	Start      Expr // nil in `xs[:end]`
+	End        Expr // nil in `xs[start:]`
+}
+
 // #######################################################
 // ################### Node Prefix Expr ##################😀
 // #######################################################
@@ -98,6 +131,16 @@ type NodeFunctionCall struct {
 	Parameters []Expr
 }
 
+// #######################################################
+// ################## Node Function Expr #################
+// #######################################################
+type NodeFunctionExpr struct {
+	Expression
+	Token      token.Token
+	Arguements []Expr
+	Body       Stmt
+}
+
 // #######################################################
 // ################### Node Struct Expr ##################😀
 // #######################################################
@@ -119,13 +162,33 @@ type NodeTernaryExpr struct {
 	ElseArm   Expr
 }
 
+// #######################################################
+// ###################### Node If Expr ###################😀
+// #######################################################
+type NodeIfExpr struct {
+	Expression
+	Token       token.Token
+	Conditional *NodeConditionalStmt // the value is the one of the arm taken
+}
+
+// #######################################################
+// ################# Node Typed Arguement ################😀
+// #######################################################
+type NodeTypedArguement struct {
+	Expression
+	Token     token.Token
+	Arguement *NodeIdentifier
+	Interface *NodeIdentifier // the interface the value must implement
+}
+
 // #######################################################
 // ##################### Node Map Expr ###################
 // #######################################################
 type NodeMapExpr struct {
 	Expression
-	Token token.Token
-	Map   map[Expr]Expr
+	Token  token.Token
+	Keys   []Expr // in source order, a bare identifier key is a NodeString
+	Values []Expr // Values[i] belong to Keys[i]
 }
 
 // #######################################################
@@ -163,3 +226,15 @@ type NodeImportExpr struct {
 	Token token.Token
 	Path  Expr
 }
+
+// #######################################################
+// ##################### Node Range Expr #################😀
+// #######################################################
+type NodeRangeExpr struct {
+	Expression
+	Token     token.Token
+	Start     Expr
+	End       Expr
+	Step      Expr // nil when no step is given
+	Inclusive bool // `..` include the end, `..<` does not
+}
@@ -0,0 +1,109 @@
+package ast
+
+import (
+	"kat/token"
+)
+
+// Simulate Tagged Union
+// This mean following nodes can either be Pattern or Node
+type Pattern struct{}
+
+func (p Pattern) pattern() {}
+func (p Pattern) node()    {}
+
+// #######################################################
+// #################### Node Match Expr ##################😀
+// #######################################################
+type NodeMatchExpr struct {
+	Expression
+	Token   token.Token
+	Subject Expr
+	Arms    []*NodeMatchArm
+}
+
+// #######################################################
+// #################### Node Match Arm ###################😀
+// #######################################################
+type NodeMatchArm struct {
+	Token   token.Token
+	Pattern Pat
+	Guard   Expr // nil when the arm has no `if`
+	Body    Stmt // a block or a single expression
+}
+
+// #######################################################
+// ################# Node Wildcard Pattern ###############😀
+// #######################################################
+type NodeWildcardPattern struct {
+	Pattern
+	Token token.Token
+}
+
+// #######################################################
+// ################# Node Literal Pattern ################😀
+// #######################################################
+type NodeLiteralPattern struct {
+	Pattern
+	Token token.Token
+	Value Expr // a number, a string, a bool or null
+}
+
+// #######################################################
+// ################# Node Binding Pattern ################😀
+// #######################################################
+type NodeBindingPattern struct {
+	Pattern
+	Token token.Token
+	Name  string
+}
+
+// #######################################################
+// ############### Node Alternative Pattern ##############😀
+// #######################################################
+type NodeAlternativePattern struct {
+	Pattern
+	Token        token.Token
+	Alternatives []Pat
+}
+
+// #######################################################
+// ################## Node Array Pattern #################😀
+// #######################################################
+type NodeArrayPattern struct {
+	Pattern
+	Token    token.Token
+	Elements []Pat
+	Rest     Pat // nil without `..`, a wildcard for a bare `..`
+}
+
+// #######################################################
+// ################### Node Map Pattern ##################😀
+// #######################################################
+type NodeMapPattern struct {
+	Pattern
+	Token  token.Token
+	Keys   []Expr
+	Values []Pat // Values[i] match the value under Keys[i]
+}
+
+// #######################################################
+// ################# Node Struct Pattern #################😀
+// #######################################################
+type NodeStructPattern struct {
+	Pattern
+	Token  token.Token
+	Name   string
+	Fields []string
+	Values []Pat // Values[i] match the field Fields[i]
+}
+
+// #######################################################
+// ################## Node Enum Pattern ##################😀
+// #######################################################
+type NodeEnumPattern struct {
+	Pattern
+	Token   token.Token
+	Enum    string
+	Variant string
+	Values  []Pat // nil without parentheses, the values are then ignored
+}
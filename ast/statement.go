@@ -22,7 +22,7 @@ type NodeProgram struct {
 }
 
 func (np *NodeProgram) String() string {
-	litter.Config.FieldExclusions = regexp.MustCompile(`^(Token|Statement|Expression)$`)
+	litter.Config.FieldExclusions = regexp.MustCompile(`^(Token|Statement|Expression|Pattern)$`)
 	return litter.Sdump(np)
 }
 
@@ -32,6 +32,7 @@ func (np *NodeProgram) String() string {
 type NodeModernForStmt struct {
 	Statement
 	Token     token.Token
+	Label     string // empty when the loop is not labeled
 	Condition Expr
 	Body      Stmt
 }
@@ -42,12 +43,26 @@ type NodeModernForStmt struct {
 type NodeClassicForStmt struct {
 	Statement
 	Token     token.Token
+	Label     string // empty when the loop is not labeled
 	Condition Expr
 	PreExpr   Stmt
 	PostExpr  Expr
 	Body      Stmt
 }
 
+// #######################################################
+// ################### Node For In Stmt ##################😀
+// #######################################################
+type NodeForInStmt struct {
+	Statement
+	Token    token.Token
+	Label    string // empty when the loop is not labeled
+	Key      Expr   // nil in the single variable form `for x in xs`
+	Value    Expr
+	Iterable Expr
+	Body     Stmt
+}
+
 // #######################################################
 // #################### Node Const Stmt ##################😀
 // #######################################################
@@ -65,7 +80,65 @@ type NodeStructStmt struct {
 	Statement
 	Token      token.Token
 	Identifier Expr
-	Properties []Expr
+	Fields     []*NodeStructField
+}
+
+// #######################################################
+// ################## Node Struct Field ##################😀
+// #######################################################
+type NodeStructField struct {
+	Token    token.Token
+	Name     string
+	Default  Expr // nil when the field has no default
+	Required bool
+}
+
+// #######################################################
+// ################### Node Enum Stmt ####################😀
+// #######################################################
+type NodeEnumStmt struct {
+	Statement
+	Token      token.Token
+	Identifier Expr
+	Variants   []*NodeEnumVariant
+}
+
+// #######################################################
+// ################## Node Enum Variant ##################😀
+// #######################################################
+type NodeEnumVariant struct {
+	Token  token.Token
+	Name   string
+	Fields []*NodeIdentifier // the names of the values it carry, empty for `Red`
+}
+
+// #######################################################
+// ################# Node Interface Stmt #################😀
+// #######################################################
+type NodeInterfaceStmt struct {
+	Statement
+	Token      token.Token
+	Identifier Expr
+	Methods    []*NodeInterfaceMethod
+}
+
+// #######################################################
+// ################ Node Interface Method ################😀
+// #######################################################
+type NodeInterfaceMethod struct {
+	Token      token.Token
+	Name       string
+	Arguements []Expr
+}
+
+// #######################################################
+// ##################### Node Impl Stmt ##################😀
+// #######################################################
+type NodeImplStmt struct {
+	Statement
+	Token     token.Token
+	Interface Expr
+	Struct    Expr
 }
 
 // #######################################################
@@ -124,3 +197,21 @@ type NodeBlockStmt struct {
 	Statement
 	Body []Stmt
 }
+
+// #######################################################
+// #################### Node Break stmt ##################😀
+// #######################################################
+type NodeBreakStmt struct {
+	Statement
+	Token token.Token
+	Label string // empty to break the innermost loop
+}
+
+// #######################################################
+// ################### Node Continue stmt ################😀
+// #######################################################
+type NodeContinueStmt struct {
+	Statement
+	Token token.Token
+	Label string // empty to continue the innermost loop
+}
@@ -0,0 +1,207 @@
+package diagnostic
+
+import (
+	"fmt"
+	"io"
+	"kat/token"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+type Severity string
+
+const (
+	SEVERITY_ERROR   Severity = "error"
+	SEVERITY_WARNING Severity = "warning"
+	SEVERITY_NOTE    Severity = "note"
+)
+
+type Code string
+
+const (
+	// Lexer
+	CODE_INVALID_TOKEN       Code = "E0001"
+	CODE_UNTERMINATED_STRING Code = "E0002"
+
+	// Parser
+	CODE_UNEXPECTED_TOKEN Code = "E0100"
+	CODE_INVALID_LITERAL  Code = "E0101"
+	CODE_ASSIGN_CONST     Code = "E0102"
+
+	// Evaluator
+	CODE_RUNTIME Code = "E0200"
+)
+
+// ANSI escape sequences used when rendering with colors
+const (
+	colorReset = "\033[0m"
+	colorBold  = "\033[1m"
+	colorRed   = "\033[1;31m"
+	colorYell  = "\033[1;33m"
+	colorCyan  = "\033[1;36m"
+	colorBlue  = "\033[1;34m"
+)
+
+type Diagnostic struct {
+	File     string
+	Line     int // 1-based line
+	Col      int // 1-based byte column where the underline start
+	EndCol   int // 1-based byte column where the underline end, exclusive
+	Severity Severity
+	Code     Code
+	Message  string
+}
+
+// FromToken build a diagnostic spanning the given token, a zero token
+// produce a diagnostic without position
+func FromToken(tok token.Token, severity Severity, code Code, msg string) *Diagnostic {
+	d := &Diagnostic{
+		File:     tok.File,
+		Severity: severity,
+		Code:     code,
+		Message:  msg,
+	}
+
+	if tok.Type == "" {
+		return d
+	}
+
+	width := len(tok.Value)
+
+	if tok.Type == token.EOL || tok.Type == token.EOF || width == 0 {
+		width = 1
+	}
+
+	d.Line = tok.Row + 1
+	d.Col = tok.Col + 1
+	d.EndCol = d.Col + width
+
+	return d
+}
+
+func (d *Diagnostic) HasPosition() bool {
+	return d.Line > 0
+}
+
+// Location format the position as file:line:col
+func (d *Diagnostic) Location() string {
+	file := d.File
+
+	if file == "" {
+		file = "<input>"
+	}
+
+	if !d.HasPosition() {
+		return file
+	}
+
+	return fmt.Sprintf("%s:%d:%d", file, d.Line, d.Col)
+}
+
+func (d *Diagnostic) Error() string {
+	return fmt.Sprintf("%s: %s[%s]: %s", d.Location(), d.Severity, d.Code, d.Message)
+}
+
+// Render format the diagnostic with the offending source line and a caret
+// underline, source is the content of the file the diagnostic refer to
+func (d *Diagnostic) Render(source []byte, color bool) string {
+	paint := func(c string, s string) string {
+		if !color {
+			return s
+		}
+
+		return c + s + colorReset
+	}
+
+	severityColor := colorRed
+
+	switch d.Severity {
+	case SEVERITY_WARNING:
+		severityColor = colorYell
+
+	case SEVERITY_NOTE:
+		severityColor = colorCyan
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(paint(severityColor, fmt.Sprintf("%s[%s]", d.Severity, d.Code)))
+	sb.WriteString(paint(colorBold, ": "+d.Message))
+	sb.WriteString("\n")
+
+	line, ok := sourceLine(source, d.Line)
+
+	if !d.HasPosition() || !ok {
+		if d.File != "" {
+			sb.WriteString(fmt.Sprintf(" %s %s\n", paint(colorBlue, "-->"), d.Location()))
+		}
+
+		return sb.String()
+	}
+
+	lineNo := fmt.Sprintf("%d", d.Line)
+	gutter := strings.Repeat(" ", len(lineNo))
+
+	sb.WriteString(fmt.Sprintf("%s%s %s\n", gutter, paint(colorBlue, "-->"), d.Location()))
+	sb.WriteString(fmt.Sprintf("%s %s\n", gutter, paint(colorBlue, "|")))
+	sb.WriteString(fmt.Sprintf("%s %s %s\n", paint(colorBlue, lineNo), paint(colorBlue, "|"), line))
+	sb.WriteString(fmt.Sprintf("%s %s %s\n", gutter, paint(colorBlue, "|"), paint(severityColor, underline(line, d.Col, d.EndCol))))
+
+	return sb.String()
+}
+
+// sourceLine return the 1-based line of the source without its line ending
+func sourceLine(source []byte, line int) (string, bool) {
+	lines := strings.Split(string(source), "\n")
+
+	if line < 1 || line > len(lines) {
+		return "", false
+	}
+
+	return strings.TrimRight(lines[line-1], "\r"), true
+}
+
+// underline build the caret line, tabs before the column are kept so the
+// caret line up with the source line, and multi-byte characters count as one
+func underline(line string, col int, endCol int) string {
+	start := min(max(col-1, 0), len(line))
+	end := min(max(endCol-1, start), len(line))
+
+	var sb strings.Builder
+
+	for _, ch := range line[:start] {
+		if ch == '\t' {
+			sb.WriteRune('\t')
+		} else {
+			sb.WriteRune(' ')
+		}
+	}
+
+	width := max(utf8.RuneCountInString(line[start:end]), 1)
+	sb.WriteString(strings.Repeat("^", width))
+
+	return sb.String()
+}
+
+// UseColor report whether output written to w should be colored, that is
+// when w is a terminal and NO_COLOR is not set
+func UseColor(w io.Writer) bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+
+	f, ok := w.(*os.File)
+
+	if !ok {
+		return false
+	}
+
+	stat, err := f.Stat()
+
+	if err != nil {
+		return false
+	}
+
+	return stat.Mode()&os.ModeCharDevice != 0
+}
@@ -1,19 +1,34 @@
 package environment
 
 import (
+	"errors"
 	"fmt"
 	"kat/value"
-	"log"
+)
+
+// Kind is how a binding was declared
+type Kind int
+
+const (
+	KIND_LET   Kind = iota // can be reassigned, also used for arguements, functions and structs
+	KIND_CONST             // can't be reassigned
+)
+
+var (
+	ErrUndefined = errors.New("variable is not found")
+	ErrConstant  = errors.New("constant can't be reassigned")
 )
 
 type Environment struct {
 	Envs   map[string]value.Value
+	Kinds  map[string]Kind
 	Parent *Environment
 }
 
 func New() *Environment {
 	return &Environment{
 		Envs:   make(map[string]value.Value),
+		Kinds:  make(map[string]Kind),
 		Parent: nil,
 	}
 }
@@ -21,6 +36,7 @@ func New() *Environment {
 func NewWithParent(parent *Environment) *Environment {
 	return &Environment{
 		Envs:   make(map[string]value.Value),
+		Kinds:  make(map[string]Kind),
 		Parent: parent,
 	}
 }
@@ -35,30 +51,54 @@ func (env *Environment) Get(key string) (value.Value, bool) {
 	return val, ok
 }
 
+// Declared report whether key is declared in this environment itself,
+// bindings of the parents are not considered, they can be shadowed
+func (env *Environment) Declared(key string) bool {
+	_, ok := env.Envs[key]
+	return ok
+}
+
 func (env *Environment) Set(key string, value value.Value) {
+	env.Define(key, value, KIND_LET)
+}
+
+// Define bind key in this environment with the given kind
+func (env *Environment) Define(key string, value value.Value, kind Kind) {
 	env.Envs[key] = value
+	env.Kinds[key] = kind
 }
 
-func (env *Environment) Assign(key string, value value.Value) {
-	ok := env.setWithParent(key, value)
+// KindOf return the kind of the nearest binding of key
+func (env *Environment) KindOf(key string) (Kind, bool) {
+	if _, ok := env.Envs[key]; ok {
+		return env.Kinds[key], true
+	}
 
-	if !ok {
-		msg := fmt.Sprintf("Variable %s is not found", key)
-		log.Fatal(msg)
+	if env.Parent != nil {
+		return env.Parent.KindOf(key)
 	}
+
+	return KIND_LET, false
 }
 
-func (env *Environment) setWithParent(key string, value value.Value) bool {
+// Assign update an existing binding in the nearest environment declaring
+// it, it fail with ErrUndefined when there is none and ErrConstant when
+// the binding is a const
+func (env *Environment) Assign(key string, value value.Value) error {
 	if _, ok := env.Envs[key]; ok {
+		if env.Kinds[key] == KIND_CONST {
+			return ErrConstant
+		}
+
 		env.Envs[key] = value
-		return true
+		return nil
 	}
 
 	if env.Parent != nil {
-		return env.Parent.setWithParent(key, value)
+		return env.Parent.Assign(key, value)
 	}
 
-	return false
+	return ErrUndefined
 }
 
 func (env *Environment) String() string {
@@ -0,0 +1,426 @@
+package evaluator
+
+import (
+	"fmt"
+	"kat/token"
+	"kat/value"
+	"math"
+	"strings"
+)
+
+// OverflowMode decide what happen when an integer operation overflow
+type OverflowMode int
+
+const (
+	OVERFLOW_WRAP  OverflowMode = iota // wrap around silently, the default
+	OVERFLOW_ERROR                     // stop with a runtime error
+)
+
+// EvalArithmetic apply `+ - * / %` on numbers, an int with an int stay
+// an int, as soon as one operand is a float the other one is promoted,
+// strings support `+` to concatenate and `*` by an int to repeat
+func (e *Evaluator) EvalArithmetic(operator string, tok token.Token, left value.Value, right value.Value) value.Value {
+	if _, ok := left.(*value.String); ok {
+		return stringArithmetic(operator, tok, left, right)
+	}
+
+	if _, ok := right.(*value.String); ok {
+		return stringArithmetic(operator, tok, left, right)
+	}
+
+	l, lok := left.(*value.Int)
+	r, rok := right.(*value.Int)
+
+	if lok && rok {
+		return e.intArithmetic(operator, tok, l.Value, r.Value)
+	}
+
+	lf, lok := toFloat(left)
+	rf, rok := toFloat(right)
+
+	if !lok || !rok {
+		return unsupportedOperands(operator, tok, left, right)
+	}
+
+	return e.floatArithmetic(operator, tok, lf, rf)
+}
+
+func (e *Evaluator) intArithmetic(operator string, tok token.Token, l int64, r int64) value.Value {
+	var val int64
+	var overflow bool
+
+	switch operator {
+	case "+":
+		val = l + r
+		overflow = (r > 0 && val < l) || (r < 0 && val > l)
+
+	case "-":
+		val = l - r
+		overflow = (r > 0 && val > l) || (r < 0 && val < l)
+
+	case "*":
+		val = l * r
+		overflow = l != 0 && (val/l != r || (l == -1 && r == math.MinInt64))
+
+	case "/":
+		if r == 0 {
+			return &value.Error{Value: "Division by zero", Token: tok}
+		}
+
+		val = l / r
+		overflow = l == math.MinInt64 && r == -1
+
+	case "%":
+		if r == 0 {
+			return &value.Error{Value: "Modulo by zero", Token: tok}
+		}
+
+		val = l % r
+
+	case "**":
+		// A negative exponent can't produce an int
+		if r < 0 {
+			return e.floatArithmetic(operator, tok, float64(l), float64(r))
+		}
+
+		val, overflow = intPow(l, r)
+
+	default:
+		msg := fmt.Sprintf("Unsupported operator: %s", operator)
+		return &value.Error{Value: msg, Token: tok}
+	}
+
+	if overflow && e.Overflow == OVERFLOW_ERROR {
+		msg := fmt.Sprintf("Integer overflow: %d %s %d", l, operator, r)
+		return &value.Error{Value: msg, Token: tok}
+	}
+
+	return &value.Int{Value: val}
+}
+
+func (e *Evaluator) floatArithmetic(operator string, tok token.Token, l float64, r float64) value.Value {
+	switch operator {
+	case "+":
+		return &value.Float{Value: l + r}
+
+	case "-":
+		return &value.Float{Value: l - r}
+
+	case "*":
+		return &value.Float{Value: l * r}
+
+	case "/":
+		if r == 0 {
+			return &value.Error{Value: "Division by zero", Token: tok}
+		}
+
+		return &value.Float{Value: l / r}
+
+	case "%":
+		if r == 0 {
+			return &value.Error{Value: "Modulo by zero", Token: tok}
+		}
+
+		return &value.Float{Value: math.Mod(l, r)}
+
+	case "**":
+		// 0 ** -n is 1 / 0 ** n
+		if l == 0 && r < 0 {
+			return &value.Error{Value: "Division by zero", Token: tok}
+		}
+
+		return &value.Float{Value: math.Pow(l, r)}
+
+	default:
+		msg := fmt.Sprintf("Unsupported operator: %s", operator)
+		return &value.Error{Value: msg, Token: tok}
+	}
+}
+
+func stringArithmetic(operator string, tok token.Token, left value.Value, right value.Value) value.Value {
+	switch operator {
+	case "+":
+		l, lok := left.(*value.String)
+		r, rok := right.(*value.String)
+
+		if lok && rok {
+			return &value.String{Value: l.Value + r.Value}
+		}
+
+	case "*":
+		str, ok := left.(*value.String)
+		count, isInt := right.(*value.Int)
+
+		// `3 * "ab"` repeat as well
+		if !ok {
+			str, ok = right.(*value.String)
+			count, isInt = left.(*value.Int)
+		}
+
+		if ok && isInt {
+			if count.Value < 0 {
+				msg := fmt.Sprintf("Repeat count can't be negative, got: %d", count.Value)
+				return &value.Error{Value: msg, Token: tok}
+			}
+
+			if count.Value > 0 && int64(len(str.Value)) > math.MaxInt32/count.Value {
+				msg := fmt.Sprintf("Repeated string is too long: %d * %d bytes", count.Value, len(str.Value))
+				return &value.Error{Value: msg, Token: tok}
+			}
+
+			return &value.String{Value: strings.Repeat(str.Value, int(count.Value))}
+		}
+	}
+
+	return unsupportedOperands(operator, tok, left, right)
+}
+
+// intPow compute base ** exp by squaring, overflow report whether the
+// result wrapped around
+func intPow(base int64, exp int64) (result int64, overflow bool) {
+	result = 1
+
+	for exp > 0 {
+		if exp&1 == 1 {
+			next := result * base
+			overflow = overflow || (result != 0 && next/result != base)
+			result = next
+		}
+
+		exp >>= 1
+
+		if exp > 0 {
+			next := base * base
+			overflow = overflow || (base != 0 && next/base != base)
+			base = next
+		}
+	}
+
+	return result, overflow
+}
+
+// EvalBitwise apply `& | ^ << >>`, only defined on ints
+func (e *Evaluator) EvalBitwise(operator string, tok token.Token, left value.Value, right value.Value) value.Value {
+	l, lok := left.(*value.Int)
+	r, rok := right.(*value.Int)
+
+	if !lok || !rok {
+		msg := fmt.Sprintf("Bitwise operator %s require int operands, got: %s and %s", operator, left.Type(), right.Type())
+		return &value.Error{Value: msg, Token: tok}
+	}
+
+	switch operator {
+	case "&":
+		return &value.Int{Value: l.Value & r.Value}
+
+	case "|":
+		return &value.Int{Value: l.Value | r.Value}
+
+	case "^":
+		return &value.Int{Value: l.Value ^ r.Value}
+
+	case "<<", ">>":
+		if r.Value < 0 {
+			msg := fmt.Sprintf("Negative shift count: %d", r.Value)
+			return &value.Error{Value: msg, Token: tok}
+		}
+
+		if operator == ">>" {
+			return &value.Int{Value: l.Value >> r.Value}
+		}
+
+		val := l.Value << r.Value
+
+		if val>>r.Value != l.Value && e.Overflow == OVERFLOW_ERROR {
+			msg := fmt.Sprintf("Integer overflow: %d << %d", l.Value, r.Value)
+			return &value.Error{Value: msg, Token: tok}
+		}
+
+		return &value.Int{Value: val}
+
+	default:
+		msg := fmt.Sprintf("Unsupported operator: %s", operator)
+		return &value.Error{Value: msg, Token: tok}
+	}
+}
+
+// EvalComparison apply `< > <= >=` on numbers with the same promotion
+// rules as the arithmetic, strings are compared lexicographically
+func (e *Evaluator) EvalComparison(operator string, tok token.Token, left value.Value, right value.Value) value.Value {
+	var cmp int
+
+	l, lok := left.(*value.Int)
+	r, rok := right.(*value.Int)
+
+	ls, lsok := left.(*value.String)
+	rs, rsok := right.(*value.String)
+
+	if lsok && rsok {
+		cmp = strings.Compare(ls.Value, rs.Value)
+	} else if lok && rok {
+		cmp = compare(l.Value, r.Value)
+	} else {
+		lf, lfok := toFloat(left)
+		rf, rfok := toFloat(right)
+
+		if !lfok || !rfok {
+			return unsupportedOperands(operator, tok, left, right)
+		}
+
+		// NaN is unordered, every comparison with it is false
+		if math.IsNaN(lf) || math.IsNaN(rf) {
+			return value.FALSE
+		}
+
+		// an int is compared exactly to a float, not converted to one
+		switch {
+		case lok:
+			cmp = compareIntFloat(l.Value, rf)
+		case rok:
+			cmp = -compareIntFloat(r.Value, lf)
+		default:
+			cmp = compare(lf, rf)
+		}
+	}
+
+	switch operator {
+	case "<":
+		return nativeBool(cmp < 0)
+
+	case ">":
+		return nativeBool(cmp > 0)
+
+	case "<=":
+		return nativeBool(cmp <= 0)
+
+	case ">=":
+		return nativeBool(cmp >= 0)
+
+	default:
+		msg := fmt.Sprintf("Unsupported operator: %s", operator)
+		return &value.Error{Value: msg, Token: tok}
+	}
+}
+
+// EvalIn implement `item in container`, that is a substring of a string,
+// an element of an array, a key of a map or a value of a range
+func (e *Evaluator) EvalIn(tok token.Token, item value.Value, container value.Value) value.Value {
+	switch container := container.(type) {
+	case *value.String:
+		sub, ok := item.(*value.String)
+
+		if !ok {
+			return unsupportedOperands("in", tok, item, container)
+		}
+
+		return nativeBool(strings.Contains(container.Value, sub.Value))
+
+	case *value.Array:
+		for _, v := range container.Value {
+			if value.Equal(v, item) {
+				return value.TRUE
+			}
+		}
+
+		return value.FALSE
+
+	case *value.Map[value.Value]:
+		_, ok := container.Get(item)
+		return nativeBool(ok)
+
+	case *value.Range:
+		switch item := item.(type) {
+		case *value.Int:
+			return nativeBool(container.Contains(item.Value))
+
+		case *value.Float:
+			i, ok := value.ExactInt(item.Value)
+			return nativeBool(ok && container.Contains(i))
+		}
+
+		return value.FALSE
+
+	default:
+		return unsupportedOperands("in", tok, item, container)
+	}
+}
+
+// EvalNegate apply the unary `-`
+func (e *Evaluator) EvalNegate(tok token.Token, right value.Value) value.Value {
+	switch right := right.(type) {
+	case *value.Int:
+		if right.Value == math.MinInt64 && e.Overflow == OVERFLOW_ERROR {
+			msg := fmt.Sprintf("Integer overflow: -(%d)", right.Value)
+			return &value.Error{Value: msg, Token: tok}
+		}
+
+		return &value.Int{Value: -right.Value}
+
+	case *value.Float:
+		return &value.Float{Value: -right.Value}
+
+	default:
+		msg := fmt.Sprintf("Unsupported operand type for -: %s", right.Type())
+		return &value.Error{Value: msg, Token: tok}
+	}
+}
+
+func unsupportedOperands(operator string, tok token.Token, left value.Value, right value.Value) value.Value {
+	msg := fmt.Sprintf("Unsupported operand types for %s: %s and %s", operator, left.Type(), right.Type())
+	return &value.Error{Value: msg, Token: tok}
+}
+
+// toFloat widen an int or a float to float64
+func toFloat(v value.Value) (float64, bool) {
+	switch v := v.(type) {
+	case *value.Int:
+		return float64(v.Value), true
+
+	case *value.Float:
+		return v.Value, true
+	}
+
+	return 0, false
+}
+
+func compare[T int64 | float64](l T, r T) int {
+	switch {
+	case l < r:
+		return -1
+
+	case l > r:
+		return 1
+	}
+
+	return 0
+}
+
+// compareIntFloat compare i to the non NaN f exactly, like value.Equal
+// does, converting i to a float would round it above 2^53
+func compareIntFloat(i int64, f float64) int {
+	if n, ok := value.ExactInt(f); ok {
+		return compare(i, n)
+	}
+
+	switch {
+	case f >= -math.MinInt64:
+		return -1
+
+	case f < math.MinInt64:
+		return 1
+
+	case i <= int64(math.Floor(f)):
+		// f has a fractional part, so it is above its floor
+		return -1
+	}
+
+	return 1
+}
+
+func nativeBool(b bool) value.Value {
+	if b {
+		return value.TRUE
+	}
+
+	return value.FALSE
+}
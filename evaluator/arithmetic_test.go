@@ -0,0 +1,154 @@
+package evaluator
+
+import (
+	"kat/token"
+	"kat/value"
+	"math"
+	"testing"
+)
+
+func TestEvalArithmeticPromotion(t *testing.T) {
+	e := &Evaluator{}
+
+	tests := []struct {
+		operator    string
+		left, right value.Value
+		expected    value.Value
+	}{
+		{operator: "+", left: &value.Int{Value: 1}, right: &value.Int{Value: 2}, expected: &value.Int{Value: 3}},
+		{operator: "+", left: &value.Int{Value: 1}, right: &value.Float{Value: 0.5}, expected: &value.Float{Value: 1.5}},
+		{operator: "*", left: &value.Float{Value: 2}, right: &value.Int{Value: 3}, expected: &value.Float{Value: 6}},
+		{operator: "/", left: &value.Int{Value: 7}, right: &value.Int{Value: 2}, expected: &value.Int{Value: 3}},
+		{operator: "/", left: &value.Int{Value: 7}, right: &value.Float{Value: 2}, expected: &value.Float{Value: 3.5}},
+		{operator: "**", left: &value.Int{Value: 2}, right: &value.Int{Value: -1}, expected: &value.Float{Value: 0.5}},
+	}
+
+	for _, tt := range tests {
+		result := e.EvalArithmetic(tt.operator, token.Token{}, tt.left, tt.right)
+
+		if result.Type() != tt.expected.Type() || !value.Equal(result, tt.expected) {
+			t.Errorf("%s %s %s: expected %s %s, got %s %s",
+				tt.left, tt.operator, tt.right, tt.expected.Type(), tt.expected, result.Type(), result)
+		}
+	}
+}
+
+func TestEvalArithmeticOverflow(t *testing.T) {
+	tests := []struct {
+		operator    string
+		left, right int64
+		wrapped     int64
+	}{
+		{operator: "+", left: math.MaxInt64, right: 1, wrapped: math.MinInt64},
+		{operator: "-", left: math.MinInt64, right: 1, wrapped: math.MaxInt64},
+		{operator: "*", left: math.MaxInt64, right: 2, wrapped: -2},
+		{operator: "/", left: math.MinInt64, right: -1, wrapped: math.MinInt64},
+		{operator: "**", left: 2, right: 64, wrapped: 0},
+	}
+
+	for _, tt := range tests {
+		left, right := &value.Int{Value: tt.left}, &value.Int{Value: tt.right}
+
+		wrap := &Evaluator{Overflow: OVERFLOW_WRAP}
+		result := wrap.EvalArithmetic(tt.operator, token.Token{}, left, right)
+
+		if i, ok := result.(*value.Int); !ok || i.Value != tt.wrapped {
+			t.Errorf("wrap %d %s %d: expected %d, got %s", tt.left, tt.operator, tt.right, tt.wrapped, result)
+		}
+
+		strict := &Evaluator{Overflow: OVERFLOW_ERROR}
+		result = strict.EvalArithmetic(tt.operator, token.Token{}, left, right)
+
+		if _, ok := result.(*value.Error); !ok {
+			t.Errorf("error %d %s %d: expected an overflow error, got %s", tt.left, tt.operator, tt.right, result)
+		}
+	}
+
+	// An operation that doesn't overflow is fine in both modes
+	strict := &Evaluator{Overflow: OVERFLOW_ERROR}
+	result := strict.EvalArithmetic("+", token.Token{}, &value.Int{Value: math.MaxInt64 - 1}, &value.Int{Value: 1})
+
+	if i, ok := result.(*value.Int); !ok || i.Value != math.MaxInt64 {
+		t.Errorf("expected %d, got %s", int64(math.MaxInt64), result)
+	}
+}
+
+func TestEvalArithmeticDivisionByZero(t *testing.T) {
+	e := &Evaluator{}
+
+	for _, operator := range []string{"/", "%"} {
+		for _, right := range []value.Value{&value.Int{Value: 0}, &value.Float{Value: 0}} {
+			result := e.EvalArithmetic(operator, token.Token{}, &value.Int{Value: 1}, right)
+
+			if _, ok := result.(*value.Error); !ok {
+				t.Errorf("1 %s %s: expected an error, got %s", operator, right, result)
+			}
+		}
+	}
+
+	// 0 ** -1 is 1 / 0
+	for _, left := range []value.Value{&value.Int{Value: 0}, &value.Float{Value: 0}} {
+		for _, right := range []value.Value{&value.Int{Value: -1}, &value.Float{Value: -0.5}} {
+			result := e.EvalArithmetic("**", token.Token{}, left, right)
+
+			if _, ok := result.(*value.Error); !ok {
+				t.Errorf("%s ** %s: expected an error, got %s", left, right, result)
+			}
+		}
+	}
+}
+
+func TestEvalComparisonIntFloat(t *testing.T) {
+	e := &Evaluator{}
+
+	tests := []struct {
+		operator    string
+		left, right value.Value
+		expected    bool
+	}{
+		// 2^53 + 1 isn't a float, converting it would make it equal to 2^53
+		{operator: ">", left: &value.Int{Value: 1<<53 + 1}, right: &value.Float{Value: 1 << 53}, expected: true},
+		{operator: "<", left: &value.Float{Value: 1 << 53}, right: &value.Int{Value: 1<<53 + 1}, expected: true},
+		{operator: ">=", left: &value.Int{Value: 1 << 53}, right: &value.Float{Value: 1 << 53}, expected: true},
+		{operator: "<", left: &value.Int{Value: math.MaxInt64}, right: &value.Float{Value: 1 << 63}, expected: true},
+		{operator: ">", left: &value.Int{Value: math.MinInt64}, right: &value.Float{Value: -(1 << 63)}, expected: false},
+		{operator: "<", left: &value.Int{Value: 2}, right: &value.Float{Value: 2.5}, expected: true},
+		{operator: ">", left: &value.Int{Value: -2}, right: &value.Float{Value: -2.5}, expected: true},
+		{operator: "<=", left: &value.Float{Value: -2.5}, right: &value.Int{Value: -3}, expected: false},
+		{operator: "<", left: &value.Int{Value: 1}, right: &value.Float{Value: math.Inf(1)}, expected: true},
+		{operator: "<=", left: &value.Int{Value: 1}, right: &value.Float{Value: math.NaN()}, expected: false},
+		{operator: ">=", left: &value.Float{Value: math.NaN()}, right: &value.Int{Value: 1}, expected: false},
+	}
+
+	for _, tt := range tests {
+		result := e.EvalComparison(tt.operator, token.Token{}, tt.left, tt.right)
+
+		if result != nativeBool(tt.expected) {
+			t.Errorf("%s %s %s: expected %t, got %s", tt.left, tt.operator, tt.right, tt.expected, result)
+		}
+	}
+}
+
+func TestEvalInRange(t *testing.T) {
+	e := &Evaluator{}
+	r := &value.Range{Start: 0, End: 3, Step: 1}
+
+	tests := []struct {
+		item     value.Value
+		expected bool
+	}{
+		{item: &value.Int{Value: 1}, expected: true},
+		{item: &value.Float{Value: 1}, expected: true},
+		{item: &value.Float{Value: 1.5}, expected: false},
+		{item: &value.Float{Value: 3}, expected: false},
+		{item: &value.String{Value: "1"}, expected: false},
+	}
+
+	for _, tt := range tests {
+		result := e.EvalIn(token.Token{}, tt.item, r)
+
+		if result != nativeBool(tt.expected) {
+			t.Errorf("%s in %s: expected %t, got %s", tt.item, r, tt.expected, result)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package evaluator
+
+import (
+	"fmt"
+	"kat/ast"
+	"kat/environment"
+	"kat/token"
+	"kat/util"
+	"kat/value"
+	"strings"
+)
+
+// lvalue is a place a value can be stored in, the target of an assignment,
+// `++` or `--`. The sub expressions of the target are evaluated once when
+// it is resolved, so `arr[next()] += 1` only call next once
+type lvalue struct {
+	get func() value.Value                // return the current value or an error
+	set func(val value.Value) value.Value // return an error or nil
+}
+
+// resolveLvalue turn an identifier, `container[index]` or `receiver.field`
+// into a place, it updates the existing binding or container in place
+func (e *Evaluator) resolveLvalue(target ast.Expr, tok token.Token, env *environment.Environment) (*lvalue, value.Value) {
+	switch node := target.(type) {
+	case *ast.NodeIdentifier:
+		return &lvalue{
+			get: func() value.Value {
+				return e.EvaluateIdentifier(node, env)
+			},
+			set: func(val value.Value) value.Value {
+				switch env.Assign(node.Name, val) {
+				case environment.ErrConstant:
+					msg := fmt.Sprintf("Cannot assign to constant %s", node.Name)
+					return &value.Error{Value: msg, Token: node.Token}
+
+				case environment.ErrUndefined:
+					msg := fmt.Sprintf("Variable %s is not found", node.Name)
+					return &value.Error{Value: msg, Token: node.Token}
+				}
+
+				return nil
+			},
+		}, nil
+
+	case *ast.NodeIndexExpr:
+		container := e.Eval(node.Identifier, env)
+		if e.Error(container) {
+			return nil, container
+		}
+
+		index := e.Eval(node.Index, env)
+		if e.Error(index) {
+			return nil, index
+		}
+
+		return e.resolveIndex(container, index, node.Token)
+
+	case *ast.NodeBinaryExpr:
+		if node.Operator != "." {
+			break
+		}
+
+		receiver := e.Eval(node.Left, env)
+		if e.Error(receiver) {
+			return nil, receiver
+		}
+
+		field, ok := node.Right.(*ast.NodeIdentifier)
+
+		if !ok {
+			break
+		}
+
+		return e.resolveField(receiver, field.Name, field.Token, env)
+	}
+
+	msg := "Invalid assignment target"
+	return nil, &value.Error{Value: msg, Token: tok}
+}
+
+func (e *Evaluator) resolveIndex(container value.Value, index value.Value, tok token.Token) (*lvalue, value.Value) {
+	if value.IsFrozen(container) {
+		msg := fmt.Sprintf("Cannot mutate a frozen %s", container.Type())
+		return nil, &value.Error{Value: msg, Token: tok}
+	}
+
+	switch container := container.(type) {
+	case *value.Array:
+		if _, err := sequenceIndex("Array", index, len(container.Value), tok); err != nil {
+			return nil, err
+		}
+
+		// The index is checked again on use, evaluating the value can
+		// resize the array, eg: `a[2] = a.pop()`
+		return &lvalue{
+			get: func() value.Value {
+				i, err := sequenceIndex("Array", index, len(container.Value), tok)
+
+				if err != nil {
+					return err
+				}
+
+				return container.Value[i]
+			},
+			set: func(val value.Value) value.Value {
+				i, err := sequenceIndex("Array", index, len(container.Value), tok)
+
+				if err != nil {
+					return err
+				}
+
+				container.Value[i] = val
+				return nil
+			},
+		}, nil
+
+	case *value.Map[value.Value]:
+		if err := mapKey(index, tok); err != nil {
+			return nil, err
+		}
+
+		return &lvalue{
+			get: func() value.Value {
+				val, ok := container.Get(index)
+
+				if !ok {
+					msg := fmt.Sprintf("Map index %s is not found", index)
+					return &value.Error{Value: msg, Token: tok}
+				}
+
+				return val
+			},
+			set: func(val value.Value) value.Value {
+				container.Set(index, val)
+				return nil
+			},
+		}, nil
+
+	case *value.Null:
+		msg := "Null dereference: can't index null"
+		return nil, &value.Error{Value: msg, Token: tok}
+
+	case *value.String:
+		msg := "Strings are immutable, can't assign to an index"
+		return nil, &value.Error{Value: msg, Token: tok}
+
+	default:
+		msg := fmt.Sprintf("Unsupported index assignment on type %s", util.TypeOf(container))
+		return nil, &value.Error{Value: msg, Token: tok}
+	}
+}
+
+func (e *Evaluator) resolveField(receiver value.Value, field string, tok token.Token, env *environment.Environment) (*lvalue, value.Value) {
+	if _, isNull := receiver.(*value.Null); isNull {
+		msg := fmt.Sprintf("Null dereference: can't assign to field %s of null", field)
+		return nil, &value.Error{Value: msg, Token: tok}
+	}
+
+	instance, ok := receiver.(*value.Struct[value.Value])
+
+	if !ok {
+		msg := fmt.Sprintf("Can't assign to field %s of %s", field, receiver.Type())
+		return nil, &value.Error{Value: msg, Token: tok}
+	}
+
+	if instance.Frozen {
+		msg := fmt.Sprintf("Cannot mutate a frozen %s", instance.Name)
+		return nil, &value.Error{Value: msg, Token: tok}
+	}
+
+	if instance.Field(field) == nil {
+		msg := fmt.Sprintf("Unknown field %s on %s", field, instance.Name)
+		return nil, &value.Error{Value: msg, Token: tok}
+	}
+
+	return &lvalue{
+		get: func() value.Value {
+			return instance.Map[field]
+		},
+		set: func(val value.Value) value.Value {
+			instance.Map[field] = val
+			return nil
+		},
+	}, nil
+}
+
+// EvalAssignment evaluate `target = value` and the compound forms such as
+// `target += value`, the result is the assigned value
+func (e *Evaluator) EvalAssignment(stmt *ast.NodeBinaryExpr, env *environment.Environment) value.Value {
+	target, err := e.resolveLvalue(stmt.Left, stmt.Token, env)
+
+	if err != nil {
+		return err
+	}
+
+	val := e.Eval(stmt.Right, env)
+
+	if e.Error(val) {
+		return val
+	}
+
+	if stmt.Operator != "=" {
+		current := target.get()
+
+		if e.Error(current) {
+			return current
+		}
+
+		val = e.EvalArithmetic(strings.TrimSuffix(stmt.Operator, "="), stmt.Token, current, val)
+
+		if e.Error(val) {
+			return val
+		}
+	}
+
+	if err := target.set(val); err != nil {
+		return err
+	}
+
+	return val
+}
+
+// EvalIncrement evaluate `++` and `--`, a prefix one return the updated
+// value and a postfix one the previous value. An operand that is not a
+// place, like `++5`, is only computed
+func (e *Evaluator) EvalIncrement(operator string, tok token.Token, operand ast.Expr, prefix bool, env *environment.Environment) value.Value {
+	var target *lvalue
+
+	if ast.IsAssignable(operand) {
+		resolved, err := e.resolveLvalue(operand, tok, env)
+
+		if err != nil {
+			return err
+		}
+
+		target = resolved
+	}
+
+	var current value.Value
+
+	if target != nil {
+		current = target.get()
+	} else {
+		current = e.Eval(operand, env)
+	}
+
+	if e.Error(current) {
+		return current
+	}
+
+	if _, ok := toFloat(current); !ok {
+		msg := fmt.Sprintf("Unsupported operator: %s for type %s", operator, current.Type())
+		return &value.Error{Value: msg, Token: tok}
+	}
+
+	updated := e.EvalArithmetic(operator[:1], tok, current, &value.Int{Value: 1})
+
+	if e.Error(updated) {
+		return updated
+	}
+
+	if target != nil {
+		if err := target.set(updated); err != nil {
+			return err
+		}
+	}
+
+	if prefix {
+		return updated
+	}
+
+	return current
+}
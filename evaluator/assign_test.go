@@ -0,0 +1,65 @@
+package evaluator
+
+import (
+	"kat/ast"
+	"kat/environment"
+	"kat/lexer"
+	"kat/parser"
+	"kat/token"
+	"kat/value"
+	"strings"
+	"testing"
+)
+
+func evalSource(t *testing.T, source string) value.Value {
+	t.Helper()
+
+	program, errs := parser.New(lexer.NewWithFile("test.kat", []byte(source))).ParseProgram()
+
+	if len(errs) != 0 {
+		t.Fatalf("unexpected parse errors: %v", errs)
+	}
+
+	return New(program).Eval(program, environment.New())
+}
+
+func TestEvalAssignmentArrayResizedByValue(t *testing.T) {
+	tests := []string{
+		"let a = [1, 2, 3]\na[2] = a.pop()\n",
+		"let a = [1, 2, 3]\na[2] += a.pop()\n",
+	}
+
+	for _, source := range tests {
+		err, ok := evalSource(t, source).(*value.Error)
+
+		if !ok {
+			t.Errorf("%q: expected an error", source)
+			continue
+		}
+
+		if !strings.Contains(err.Value, "out of range") {
+			t.Errorf("%q: expected an out of range error, got %s", source, err.Value)
+		}
+	}
+}
+
+func TestEvalAssignmentInvalidField(t *testing.T) {
+	// `a.1 = 2`, the parser rejects it but the evaluator must not panic
+	a := &ast.NodeIdentifier{Token: token.Token{Type: token.IDENTIFIER, Value: "a"}, Name: "a"}
+	stmt := &ast.NodeBinaryExpr{
+		Operator: "=",
+		Left: &ast.NodeBinaryExpr{
+			Operator: ".",
+			Left:     a,
+			Right:    &ast.NodeInteger{Value: 1},
+		},
+		Right: &ast.NodeInteger{Value: 2},
+	}
+
+	env := environment.New()
+	env.Set("a", &value.Int{Value: 0})
+
+	if _, ok := (&Evaluator{}).Eval(stmt, env).(*value.Error); !ok {
+		t.Errorf("expected an error assigning to a.1")
+	}
+}
@@ -0,0 +1,79 @@
+package evaluator
+
+import (
+	"fmt"
+	"kat/ast"
+	"kat/environment"
+	"kat/token"
+	"kat/value"
+	"slices"
+)
+
+func (e *Evaluator) EvalEnumStmt(stmt *ast.NodeEnumStmt, env *environment.Environment) value.Value {
+	identifier, ok := stmt.Identifier.(*ast.NodeIdentifier)
+
+	if !ok {
+		msg := fmt.Sprintf("Invalid identifier: %s", stmt.Identifier)
+		return &value.Error{Value: msg, Token: stmt.Token}
+	}
+
+	if env.Declared(identifier.Name) {
+		msg := fmt.Sprintf("Symbol %s already exists", identifier.Name)
+		return &value.Error{Value: msg, Token: stmt.Token}
+	}
+
+	enum := &value.Enum{Name: identifier.Name}
+
+	for _, v := range stmt.Variants {
+		variant := &value.EnumVariant{Name: v.Name}
+
+		for _, field := range v.Fields {
+			variant.Fields = append(variant.Fields, field.Name)
+		}
+
+		enum.Variants = append(enum.Variants, variant)
+	}
+
+	env.Set(identifier.Name, enum)
+	return value.NULL
+}
+
+// enumMember evaluate `Enum.Variant`, a variant without values is the
+// value itself and one carrying values is a function building it
+func (e *Evaluator) enumMember(enum *value.Enum, name string, tok token.Token) value.Value {
+	variant := enum.Variant(name)
+
+	if variant == nil {
+		msg := fmt.Sprintf("Unknown variant %s on %s", name, enum.Name)
+		return &value.Error{Value: msg, Token: tok}
+	}
+
+	if len(variant.Fields) == 0 {
+		return &value.EnumValue{Enum: enum, Variant: variant}
+	}
+
+	return &value.WrapperFunction{
+		Name: fmt.Sprintf("%s.%s", enum.Name, name),
+		Fn: func(varargs ...value.Value) value.Value {
+			if len(varargs) != len(variant.Fields) {
+				msg := fmt.Sprintf("Bad variant values for %s.%s, expected %d, got %d", enum.Name, name, len(variant.Fields), len(varargs))
+				return &value.Error{Value: msg}
+			}
+
+			return &value.EnumValue{Enum: enum, Variant: variant, Values: slices.Clone(varargs)}
+		},
+	}
+}
+
+// enumField evaluate `value.field` on an enum value, the fields are the
+// names given to the values in the declaration of the variant
+func (e *Evaluator) enumField(val *value.EnumValue, name string, tok token.Token) value.Value {
+	i := slices.Index(val.Variant.Fields, name)
+
+	if i < 0 {
+		msg := fmt.Sprintf("Unknown field %s on %s", name, val)
+		return &value.Error{Value: msg, Token: tok}
+	}
+
+	return val.Values[i]
+}
@@ -5,25 +5,32 @@ import (
 	"kat/ast"
 	"kat/environment"
 	"kat/stdlib"
+	"kat/token"
 	"kat/util"
 	"kat/value"
+	"slices"
 )
 
 type Evaluator struct {
-	Errors []error
-	Tree   ast.Stmt
+	Errors      []error
+	Tree        ast.Stmt
+	Overflow    OverflowMode // what to do when integer arithmetic overflow
+	FreezeConst bool         // deep freeze the arrays, maps and structs bound to a const
 }
 
-var Pkgs = &value.Map[value.Value]{KeyVal: &value.KeyVal[value.Value]{Map: make(map[string]value.Value)}}
+var Pkgs = &value.KeyVal[value.Value]{Map: make(map[string]value.Value)}
 
 func init() {
 	// Register the standard library
 
 	// fmt package
-	Pkgs.Map["fmt"] = &value.Map[value.Value]{&value.KeyVal[value.Value]{stdlib.FmtFuncs}}
+	Pkgs.Map["fmt"] = &value.KeyVal[value.Value]{Map: stdlib.FmtFuncs}
 
 	// fmt io
-	Pkgs.Map["io"] = &value.Map[value.Value]{&value.KeyVal[value.Value]{stdlib.IoFuncs}}
+	Pkgs.Map["io"] = &value.KeyVal[value.Value]{Map: stdlib.IoFuncs}
+
+	// os package
+	Pkgs.Map["os"] = &value.KeyVal[value.Value]{Map: stdlib.OsFuncs}
 }
 
 func New(tree ast.Stmt) *Evaluator {
@@ -41,6 +48,11 @@ func (e *Evaluator) Eval(astNode ast.Node, env *environment.Environment) value.V
 		return e.EvalProgram(stmt, env)
 
 	case *ast.NodeExprStmt:
+		// A match used as a statement let its arms return, break or continue
+		if match, ok := stmt.Expr.(*ast.NodeMatchExpr); ok {
+			return e.EvalMatchExpr(match, env)
+		}
+
 		return e.Eval(stmt.Expr, env)
 
 	case *ast.NodeInteger:
@@ -55,6 +67,9 @@ func (e *Evaluator) Eval(astNode ast.Node, env *environment.Environment) value.V
 	case *ast.NodeString:
 		return &value.String{stmt.Value}
 
+	case *ast.NodeNull:
+		return value.NULL
+
 	case *ast.NodeBinaryExpr:
 		return e.EvaluateBinaryExpr(stmt, env)
 
@@ -79,9 +94,27 @@ func (e *Evaluator) Eval(astNode ast.Node, env *environment.Environment) value.V
 	case *ast.NodeReturnStmt:
 		return e.EvalReturnStmt(result, stmt, env)
 
+	case *ast.NodeFunctionExpr:
+		return e.EvalFunctionExpr(stmt, env)
+
+	case *ast.NodeBreakStmt:
+		return &value.Break{Label: stmt.Label}
+
+	case *ast.NodeContinueStmt:
+		return &value.Continue{Label: stmt.Label}
+
 	case *ast.NodeConditionalStmt:
 		return e.EvalConditionalStmt(stmt, env)
 
+	case *ast.NodeEnumStmt:
+		return e.EvalEnumStmt(stmt, env)
+
+	case *ast.NodeInterfaceStmt:
+		return e.EvalInterfaceStmt(stmt, env)
+
+	case *ast.NodeImplStmt:
+		return e.EvalImplStmt(stmt, env)
+
 	case *ast.NodeStructStmt:
 		return e.EvalStructStmt(stmt, env)
 
@@ -97,6 +130,12 @@ func (e *Evaluator) Eval(astNode ast.Node, env *environment.Environment) value.V
 	case *ast.NodeClassicForStmt:
 		return e.EvalClassicForStmt(stmt, env)
 
+	case *ast.NodeForInStmt:
+		return e.EvalForInStmt(stmt, env)
+
+	case *ast.NodeRangeExpr:
+		return e.EvalRangeExpr(stmt, env)
+
 	case *ast.NodePostfixExpr:
 		return e.EvalPostfixExpr(stmt, env)
 
@@ -109,73 +148,27 @@ func (e *Evaluator) Eval(astNode ast.Node, env *environment.Environment) value.V
 	case *ast.NodeArrayExpr:
 		return e.EvalArrayExpr(stmt, env)
 
+	case *ast.NodeTernaryExpr:
+		return e.EvalTernaryExpr(stmt, env)
+
+	case *ast.NodeIfExpr:
+		return e.EvalIfExpr(stmt, env)
+
+	case *ast.NodeMatchExpr:
+		return consumedValue(e.EvalMatchExpr(stmt, env), "a match expression", stmt.Token)
+
 	case *ast.NodeIndexExpr:
 		return e.EvalIndexExpr(stmt, env)
 
+	case *ast.NodeSliceExpr:
+		return e.EvalSliceExpr(stmt, env)
+
 	case *ast.NodeSelf:
 		return e.EvalSelf(stmt, env)
 
 	default:
 		msg := fmt.Sprintf("Unrecognized statement type: %T", stmt)
-		return &value.Error{msg}
-	}
-}
-
-func (e *Evaluator) EvalIndexExpr(stmt *ast.NodeIndexExpr, env *environment.Environment) value.Value {
-	identifier := e.Eval(stmt.Identifier, env)
-
-	if e.Error(identifier) {
-		return identifier
-	}
-
-	switch node := identifier.(type) {
-	case *value.Array:
-		idx := e.Eval(stmt.Index, env)
-
-		if e.Error(idx) {
-			return idx
-		}
-
-		index, ok := idx.(*value.Int)
-
-		if !ok {
-			msg := "Array index is not an int"
-			return &value.Error{msg}
-		}
-
-		if index.Value > int64(len(node.Value)) {
-			msg := "Array index out of range"
-			return &value.Error{msg}
-		}
-
-		return node.Value[index.Value]
-
-	case *value.Map[value.Value]:
-		idx := e.Eval(stmt.Index, env)
-
-		if e.Error(idx) {
-			return idx
-		}
-
-		index, ok := idx.(*value.String)
-
-		if !ok {
-			msg := "Map index is not a string"
-			return &value.Error{msg}
-		}
-
-		val, ok := node.Map[index.Value]
-
-		if !ok {
-			msg := fmt.Sprintf("Map index %s is not found", index.Value)
-			return &value.Error{msg}
-		}
-
-		return val
-
-	default:
-		msg := fmt.Sprintf("Unsupported index access on type %s", util.TypeOf(identifier))
-		return &value.Error{msg}
+		return &value.Error{Value: msg}
 	}
 }
 
@@ -184,7 +177,7 @@ func (e *Evaluator) EvalSelf(stmt *ast.NodeSelf, env *environment.Environment) v
 
 	if !ok {
 		msg := fmt.Sprintf("Symbol %s is not found", stmt.Name)
-		return &value.Error{msg}
+		return &value.Error{Value: msg, Token: stmt.Token}
 	}
 
 	return self
@@ -203,7 +196,7 @@ func (e *Evaluator) EvalArrayExpr(stmt *ast.NodeArrayExpr, env *environment.Envi
 		values = append(values, val)
 	}
 
-	return &value.Array{values}
+	return &value.Array{Value: values}
 }
 
 func (e *Evaluator) EvalImportExpr(stmt *ast.NodeImportExpr, env *environment.Environment) value.Value {
@@ -217,111 +210,54 @@ func (e *Evaluator) EvalImportExpr(stmt *ast.NodeImportExpr, env *environment.En
 
 	if !ok {
 		msg := fmt.Sprintf("Package %s not found", path.(*value.String).Value)
-		return &value.Error{msg}
+		return &value.Error{Value: msg, Token: stmt.Token}
 	}
 
 	return &value.Module{pkg}
 }
 
 func (e *Evaluator) EvalPrefixExpr(stmt *ast.NodePrefixExpr, env *environment.Environment) value.Value {
+	if stmt.Operator == "++" || stmt.Operator == "--" {
+		return e.EvalIncrement(stmt.Operator, stmt.Token, stmt.Right, true, env)
+	}
+
 	right := e.Eval(stmt.Right, env)
 
 	if e.Error(right) {
 		return right
 	}
 
-	ident, isIdent := stmt.Right.(*ast.NodeIdentifier)
-
 	switch stmt.Operator {
-	case "++":
-		switch right.(type) {
-		case *value.Int:
-			if isIdent {
-				env.Set(ident.Name, &value.Int{right.(*value.Int).Value + 1})
-			}
-
-			return &value.Int{right.(*value.Int).Value + 1}
-
-		default:
-			msg := fmt.Sprintf("Unsupported operator: %s for type %s", stmt.Operator, util.TypeOf(right))
-			return &value.Error{msg}
-		}
+	case "-":
+		return e.EvalNegate(stmt.Token, right)
 
-	case "--":
-		switch right.(type) {
-		case *value.Int:
-			if isIdent {
-				env.Set(ident.Name, &value.Int{right.(*value.Int).Value - 1})
-			}
+	case "!":
+		return nativeBool(!value.IsTruthy(right))
 
-			return &value.Int{right.(*value.Int).Value - 1}
+	case "~":
+		integer, ok := right.(*value.Int)
 
-		default:
-			msg := fmt.Sprintf("Unsupported operator: %s for type %s", stmt.Operator, util.TypeOf(right))
-			return &value.Error{msg}
+		if !ok {
+			msg := fmt.Sprintf("Bitwise operator ~ require an int operand, got: %s", right.Type())
+			return &value.Error{Value: msg, Token: stmt.Token}
 		}
 
-	case "-":
-		switch right.(type) {
-		case *value.Int:
-			if isIdent {
-				env.Set(ident.Name, &value.Int{-right.(*value.Int).Value})
-			}
-
-			return &value.Int{-right.(*value.Int).Value}
-
-		default:
-			msg := fmt.Sprintf("Unsupported operator: %s for type %s", stmt.Operator, util.TypeOf(right))
-			return &value.Error{msg}
-		}
+		return &value.Int{Value: ^integer.Value}
 
 	default:
 		msg := fmt.Sprintf("Unsupported operator: %s", stmt.Operator)
-		return &value.Error{msg}
+		return &value.Error{Value: msg, Token: stmt.Token}
 	}
 }
 
 func (e *Evaluator) EvalPostfixExpr(stmt *ast.NodePostfixExpr, env *environment.Environment) value.Value {
-	left := e.Eval(stmt.Left, env)
-
-	if e.Error(left) {
-		return left
-	}
-
-	ident, isIdent := stmt.Left.(*ast.NodeIdentifier)
-
 	switch stmt.Operator {
-	case "++":
-		switch left.(type) {
-		case *value.Int:
-			if isIdent {
-				env.Set(ident.Name, &value.Int{left.(*value.Int).Value + 1})
-			}
-
-			return &value.Int{left.(*value.Int).Value}
-
-		default:
-			msg := fmt.Sprintf("Unsupported operator: %s for type %s", stmt.Operator, util.TypeOf(left))
-			return &value.Error{msg}
-		}
-
-	case "--":
-		switch left.(type) {
-		case *value.Int:
-			if isIdent {
-				env.Set(ident.Name, &value.Int{left.(*value.Int).Value - 1})
-			}
-
-			return &value.Int{left.(*value.Int).Value}
-
-		default:
-			msg := fmt.Sprintf("Unsupported operator: %s for type %s", stmt.Operator, util.TypeOf(left))
-			return &value.Error{msg}
-		}
+	case "++", "--":
+		return e.EvalIncrement(stmt.Operator, stmt.Token, stmt.Left, false, env)
 
 	default:
 		msg := fmt.Sprintf("Unsupported operator: %s", stmt.Operator)
-		return &value.Error{msg}
+		return &value.Error{Value: msg, Token: stmt.Token}
 	}
 }
 
@@ -329,7 +265,11 @@ func (e *Evaluator) EvalClassicForStmt(stmt *ast.NodeClassicForStmt, env *enviro
 	var result = value.NULL
 	newEnv := environment.NewWithParent(env)
 
-	e.Eval(stmt.PreExpr, newEnv) // pre expression
+	pre := e.Eval(stmt.PreExpr, newEnv) // pre expression
+
+	if e.Error(pre) {
+		return pre
+	}
 
 	condition := e.Eval(stmt.Condition, newEnv)
 
@@ -337,9 +277,22 @@ func (e *Evaluator) EvalClassicForStmt(stmt *ast.NodeClassicForStmt, env *enviro
 		return condition
 	}
 
-	for util.IsTruthy(condition) {
-		e.Eval(stmt.Body, newEnv)
-		e.Eval(stmt.PostExpr, newEnv) // post expression
+	for value.IsTruthy(condition) {
+		body := e.Eval(stmt.Body, newEnv)
+
+		if stop, signal := e.loopSignal(body, stmt.Label); stop {
+			if signal != nil {
+				return signal
+			}
+
+			break
+		}
+
+		post := e.Eval(stmt.PostExpr, newEnv) // post expression
+
+		if e.Error(post) {
+			return post
+		}
 
 		condition = e.Eval(stmt.Condition, newEnv)
 
@@ -359,8 +312,16 @@ func (e *Evaluator) EvalModernForStmt(stmt *ast.NodeModernForStmt, env *environm
 		return condition
 	}
 
-	for util.IsTruthy(condition) {
-		e.Eval(stmt.Body, env)
+	for value.IsTruthy(condition) {
+		body := e.Eval(stmt.Body, env)
+
+		if stop, signal := e.loopSignal(body, stmt.Label); stop {
+			if signal != nil {
+				return signal
+			}
+
+			break
+		}
 
 		condition = e.Eval(stmt.Condition, env)
 
@@ -371,99 +332,181 @@ func (e *Evaluator) EvalModernForStmt(stmt *ast.NodeModernForStmt, env *environm
 	return result
 }
 
-func (e *Evaluator) EvalMapExpr(stmt *ast.NodeMapExpr, env *environment.Environment) value.Value {
-	keyVal := make(map[string]value.Value)
+func (e *Evaluator) EvalForInStmt(stmt *ast.NodeForInStmt, env *environment.Environment) value.Value {
+	var result value.Value = value.NULL
 
-	for k, v := range stmt.Map {
-		key, ok := k.(*ast.NodeIdentifier)
+	iterable := e.Eval(stmt.Iterable, env)
 
-		if !ok {
-			msg := fmt.Sprintf("Invalid struct key: %s", k)
-			return &value.Error{msg}
+	if e.Error(iterable) {
+		return iterable
+	}
+
+	// Run the body with its own environment holding the loop variables,
+	// it return false once the loop has to end
+	iterate := func(key value.Value, val value.Value) bool {
+		loopEnv := environment.NewWithParent(env)
+
+		if stmt.Key != nil {
+			loopEnv.Set(stmt.Key.(*ast.NodeIdentifier).Name, key)
 		}
 
-		keyVal[key.Name] = e.Eval(v, env)
+		loopEnv.Set(stmt.Value.(*ast.NodeIdentifier).Name, val)
+
+		stop, signal := e.loopSignal(e.Eval(stmt.Body, loopEnv), stmt.Label)
 
-		if e.Error(keyVal[key.Name]) {
-			return keyVal[key.Name]
+		if signal != nil {
+			result = signal
 		}
+
+		return !stop
 	}
 
-	return &value.Map[value.Value]{&value.KeyVal[value.Value]{keyVal}}
-}
+	switch iter := iterable.(type) {
+	case *value.Array:
+		for i, v := range iter.Value {
+			if !iterate(&value.Int{Value: int64(i)}, v) {
+				break
+			}
+		}
 
-func (e *Evaluator) EvalStructExpr(stmt *ast.NodeStructExpr, env *environment.Environment) value.Value {
-	ident, ok := stmt.Name.(*ast.NodeIdentifier)
+	case *value.String:
+		i := 0
 
-	if !ok {
-		msg := fmt.Sprintf("Invalid identifier: %s", stmt.Name)
-		return &value.Error{msg}
-	}
+		for _, ch := range iter.Value {
+			if !iterate(&value.Int{Value: int64(i)}, &value.String{Value: string(ch)}) {
+				break
+			}
 
-	structStmt, ok := env.Get(ident.Name)
+			i++
+		}
 
-	if !ok {
-		msg := fmt.Sprintf("Struct %s is not found", ident.Name)
-		return &value.Error{msg}
+	case *value.Map[value.Value]:
+		// Iterate a copy so the body can add or delete entries
+		entries := slices.Clone(iter.Entries)
+
+		for _, entry := range entries {
+			// `for k in map` bind the key, `for k, v in map` the key and value
+			if stmt.Key == nil {
+				if !iterate(nil, entry.Key) {
+					break
+				}
+			} else if !iterate(entry.Key, entry.Value) {
+				break
+			}
+		}
+
+	case *value.Range:
+		var i int64 = 0
+
+		for n := iter.Start; iter.Contains(n); n += iter.Step {
+			if !iterate(&value.Int{Value: i}, &value.Int{Value: n}) {
+				break
+			}
+
+			i++
+		}
+
+	case *value.Enum:
+		// The variants in declaration order, those carrying values give
+		// the function building them
+		for i, variant := range iter.Variants {
+			if !iterate(&value.Int{Value: int64(i)}, e.enumMember(iter, variant.Name, stmt.Token)) {
+				break
+			}
+		}
+
+	default:
+		msg := fmt.Sprintf("Cannot iterate over %s", iterable.Type())
+		return &value.Error{Value: msg, Token: stmt.Token}
 	}
 
-	structStmtProp := structStmt.(*value.Struct[value.Value]).Prop
-	keyMap := e.Eval(stmt.Values, env)
+	return result
+}
+
+func (e *Evaluator) EvalRangeExpr(stmt *ast.NodeRangeExpr, env *environment.Environment) value.Value {
+	bounds := []ast.Expr{stmt.Start, stmt.End}
 
-	if e.Error(keyMap) {
-		return keyMap
+	if stmt.Step != nil {
+		bounds = append(bounds, stmt.Step)
 	}
 
-	props := keyMap.(*value.Map[value.Value])
+	values := []int64{0, 0, 1}
 
-	actualProps := make([]string, 0)
-	for k := range props.Map {
-		ok := util.InArray[string](structStmtProp, k)
+	for i, bound := range bounds {
+		val := e.Eval(bound, env)
+
+		if e.Error(val) {
+			return val
+		}
+
+		integer, ok := val.(*value.Int)
 
 		if !ok {
-			msg := fmt.Sprintf("Unknown field %s on %s", k, ident.Name)
-			return &value.Error{msg}
+			msg := fmt.Sprintf("Range bounds and step must be int, got: %s", val.Type())
+			return &value.Error{Value: msg, Token: stmt.Token}
 		}
-		actualProps = append(actualProps, k)
+
+		values[i] = integer.Value
+	}
+
+	if values[2] == 0 {
+		msg := "Range step can't be 0"
+		return &value.Error{Value: msg, Token: stmt.Token}
 	}
 
-	return &value.Struct[value.Value]{ident.Name, actualProps, &value.KeyVal[value.Value]{props.Map}}
+	return &value.Range{Start: values[0], End: values[1], Step: values[2], Inclusive: stmt.Inclusive}
 }
 
-func (e *Evaluator) EvalStructStmt(stmt *ast.NodeStructStmt, env *environment.Environment) value.Value {
-	var result value.Value = value.NULL
-	identifier, ok := stmt.Identifier.(*ast.NodeIdentifier)
+// loopSignal tell a loop what to do with the result of its body, stop
+// is true when the loop must end and signal, when not nil, has to be
+// handed to the enclosing statement, that is a return, an error or a
+// break/continue aimed at an outer loop
+func (e *Evaluator) loopSignal(result value.Value, label string) (stop bool, signal value.Value) {
+	switch result := result.(type) {
+	case *value.Break:
+		if result.Label == "" || result.Label == label {
+			return true, nil
+		}
 
-	if !ok {
-		msg := fmt.Sprintf("Invalid identifier: %s", stmt.Identifier)
-		return &value.Error{msg}
-	}
+		return true, result
+
+	case *value.Continue:
+		if result.Label == "" || result.Label == label {
+			return false, nil
+		}
 
-	_, ok = env.Get(identifier.Name)
+		return true, result
 
-	if ok {
-		msg := fmt.Sprintf("Symbol %s already exists", identifier.Name)
-		return &value.Error{msg}
+	case *value.Return, *value.Error:
+		return true, result
 	}
 
-	props := make([]string, 0)
-	valKeyVal := make(map[string]value.Value)
+	return false, nil
+}
+
+func (e *Evaluator) EvalMapExpr(stmt *ast.NodeMapExpr, env *environment.Environment) value.Value {
+	valMap := value.NewMap[value.Value]()
 
-	for _, p := range stmt.Properties {
-		prop, ok := p.(*ast.NodeIdentifier)
+	for i, k := range stmt.Keys {
+		key := e.Eval(k, env)
 
-		if !ok {
-			msg := fmt.Sprintf("Invalid property: %s", p)
-			return &value.Error{msg}
+		if e.Error(key) {
+			return key
 		}
 
-		valKeyVal[prop.Name] = value.NULL
-		props = append(props, prop.Name)
+		val := e.Eval(stmt.Values[i], env)
+
+		if e.Error(val) {
+			return val
+		}
+
+		if err := valMap.Set(key, val); err != nil {
+			msg := fmt.Sprintf("Invalid map key: %s", err)
+			return &value.Error{Value: msg, Token: stmt.Token}
+		}
 	}
 
-	_struct := &value.Struct[value.Value]{identifier.Name, props, &value.KeyVal[value.Value]{Map: valKeyVal}}
-	env.Set(identifier.Name, _struct)
-	return result
+	return valMap
 }
 
 func (e *Evaluator) EvalConditionalStmt(stmt *ast.NodeConditionalStmt, env *environment.Environment) value.Value {
@@ -473,15 +516,52 @@ func (e *Evaluator) EvalConditionalStmt(stmt *ast.NodeConditionalStmt, env *envi
 		return condition
 	}
 
-	if util.IsTruthy(condition) {
+	if value.IsTruthy(condition) {
 		return e.Eval(stmt.ThenArm, env)
-	} else {
+	} else if stmt.ElseArm != nil {
 		return e.Eval(stmt.ElseArm, env)
 	}
+
+	return value.NULL
 }
 
-func (e *Evaluator) EvalReturnStmt(result value.Value, stmt ast.Node, env *environment.Environment) value.Value {
-	result = e.Eval(stmt, env)
+// EvalTernaryExpr evaluate `cond ? a : b`, only the arm taken is evaluated
+func (e *Evaluator) EvalTernaryExpr(stmt *ast.NodeTernaryExpr, env *environment.Environment) value.Value {
+	condition := e.Eval(stmt.Condition, env)
+
+	if e.Error(condition) {
+		return condition
+	}
+
+	if value.IsTruthy(condition) {
+		return e.Eval(stmt.ThenArm, env)
+	}
+
+	return e.Eval(stmt.ElseArm, env)
+}
+
+// EvalIfExpr evaluate an `if` used as a value, it is the value of the last
+// statement of the block taken, or null when no block is taken. The value
+// is consumed by the surrounding expression so the arms can't `return`,
+// `break` or `continue`
+func (e *Evaluator) EvalIfExpr(stmt *ast.NodeIfExpr, env *environment.Environment) value.Value {
+	return consumedValue(e.EvalConditionalStmt(stmt.Conditional, env), "an if expression", stmt.Token)
+}
+
+// consumedValue turn a return, break or continue coming out of an
+// expression whose value is used into an error, what name the expression
+func consumedValue(result value.Value, what string, tok token.Token) value.Value {
+	switch result.(type) {
+	case *value.Return, *value.Break, *value.Continue:
+		msg := fmt.Sprintf("Can't %s from %s", result.Type(), what)
+		return &value.Error{Value: msg, Token: tok}
+	}
+
+	return result
+}
+
+func (e *Evaluator) EvalReturnStmt(result value.Value, stmt *ast.NodeReturnStmt, env *environment.Environment) value.Value {
+	result = e.Eval(stmt.Value, env)
 
 	if e.Error(result) {
 		return result
@@ -490,19 +570,25 @@ func (e *Evaluator) EvalReturnStmt(result value.Value, stmt ast.Node, env *envir
 	return &value.Return{result}
 }
 
+// EvalBlockStmt evaluate the block in its own scope, what it declare
+// is not visible once the block end
 func (e *Evaluator) EvalBlockStmt(stmt *ast.NodeBlockStmt, env *environment.Environment) value.Value {
+	return e.evalStatements(stmt, environment.NewWithParent(env))
+}
+
+// evalStatements evaluate the statements of the block directly in env
+func (e *Evaluator) evalStatements(stmt *ast.NodeBlockStmt, env *environment.Environment) value.Value {
 	var result value.Value = value.NULL
 
 	for _, stmt := range stmt.Body {
 		result = e.Eval(stmt, env)
 
-		if e.Error(result) {
+		// Let return, break and continue reach the function
+		// or the loop they are meant for
+		switch result.(type) {
+		case *value.Error, *value.Return, *value.Break, *value.Continue:
 			return result
 		}
-
-		if _, ok := result.(*value.Return); ok {
-			return result.(*value.Return).Value
-		}
 	}
 	return result
 }
@@ -511,7 +597,7 @@ func (e *Evaluator) EvalFunctionCall(stmt *ast.NodeFunctionCall, env *environmen
 	var receiverInstance value.Value
 	var identifier value.Value
 	var identifierName string
-	var fnEnv = environment.NewWithParent(env)
+	var identifierToken = stmt.Token
 	var result value.Value = value.NULL
 
 	switch node := stmt.Identifer.(type) {
@@ -524,6 +610,17 @@ func (e *Evaluator) EvalFunctionCall(stmt *ast.NodeFunctionCall, env *environmen
 		identifierName = node.Name
 
 	case *ast.NodeBinaryExpr:
+		if node.Operator != "." && node.Operator != "?." {
+			// Any other operator produce the function, eg: `(f ?? g)(1)`
+			identifier = e.Eval(node, env)
+			if e.Error(identifier) {
+				return identifier
+			}
+
+			identifierName = identifier.String()
+			break
+		}
+
 		receiverInstance = e.Eval(node.Left, env)
 		if e.Error(receiverInstance) {
 			return receiverInstance
@@ -533,11 +630,31 @@ func (e *Evaluator) EvalFunctionCall(stmt *ast.NodeFunctionCall, env *environmen
 
 		if !ok {
 			msg := fmt.Sprintf("Invalid identifier: %s", node.Right)
-			return &value.Error{msg}
+			return &value.Error{Value: msg, Token: stmt.Token}
 		}
 
-		identifier = &value.String{ident.Name}
+		if _, isNull := receiverInstance.(*value.Null); isNull {
+			// `maybe?.method()` skip the call, arguments included
+			if node.Operator == "?." {
+				return value.NULL
+			}
+
+			msg := fmt.Sprintf("Null dereference: can't call method %s on null, use ?. to allow null", ident.Name)
+			return &value.Error{Value: msg, Token: ident.Token}
+		}
+
+		identifier = &value.String{Value: ident.Name}
 		identifierName = ident.Name
+		identifierToken = ident.Token
+
+	default:
+		// Any other expression producing a function, eg: `makeAdder(1)(2)`
+		identifier = e.Eval(node, env)
+		if e.Error(identifier) {
+			return identifier
+		}
+
+		identifierName = identifier.String()
 	}
 
 	// Params
@@ -553,184 +670,279 @@ func (e *Evaluator) EvalFunctionCall(stmt *ast.NodeFunctionCall, env *environmen
 		switch receiveryType := receiverInstance.(type) {
 
 		case *value.Struct[value.Value]:
-			receiverName := receiveryType.Name
-			_receiver, ok := env.Get(receiverName)
+			valFn, ok := receiveryType.Method(identifierName)
 
 			if !ok {
-				msg := fmt.Sprintf("Symbol %s is not found", receiverName)
-				return &value.Error{msg}
-			}
+				// A field holding a function, eg: `button.onClick()`
+				if fn, isFn := receiveryType.Map[identifierName].(*value.Function); isFn {
+					return e.CallFunction(stmt, fn, nil, params)
+				}
 
-			receiver, ok := _receiver.(*value.Struct[value.Value])
+				msg := fmt.Sprintf("Symbol %s is not found", identifierName)
+				return &value.Error{Value: msg, Token: identifierToken}
+			}
 
-			if !ok {
-				msg := fmt.Sprintf("Symbol %s is not a valid receiver", _receiver)
-				return &value.Error{msg}
+			if !isMethod(valFn) {
+				msg := fmt.Sprintf("Function %s is static, call it on the struct: %s.%s()", identifierName, receiveryType.Name, identifierName)
+				return &value.Error{Value: msg, Token: identifierToken}
 			}
 
-			_valFn, ok := receiver.Map[identifier.(*value.String).Value]
+			return e.CallFunction(stmt, valFn, receiverInstance, params)
+
+		case *value.StructType:
+			valFn, ok := receiveryType.Method(identifierName)
 
 			if !ok {
-				msg := fmt.Sprintf("Symbol %s is not found", identifier.(*value.String).Value)
-				return &value.Error{msg}
+				msg := fmt.Sprintf("Symbol %s is not found", identifierName)
+				return &value.Error{Value: msg, Token: identifierToken}
+			}
+
+			if isMethod(valFn) {
+				msg := fmt.Sprintf("Method %s needs an instance of %s, it can't be called on the struct", identifierName, receiveryType.Name)
+				return &value.Error{Value: msg, Token: identifierToken}
 			}
 
-			valFn, ok := _valFn.(*value.Function)
+			return e.CallFunction(stmt, valFn, nil, params)
+
+		case *value.Module:
+			valFn, ok := receiverInstance.(*value.Module).Value.(*value.KeyVal[value.Value]).Map[identifierName]
 
 			if !ok {
-				msg := fmt.Sprintf("Symbol %s is not a function", identifier.(*value.String).Value)
-				return &value.Error{msg}
+				msg := fmt.Sprintf("Symbol %s is not found", identifierName)
+				return &value.Error{Value: msg, Token: identifierToken}
 			}
 
-			// Start bind params to args
-			fnArgs := valFn.Args
+			fn, ok := valFn.(*value.WrapperFunction)
 
-			if len(valFn.Args) > 0 {
-				self, ok := valFn.Args[0].(*value.Self)
+			if !ok {
+				return result
+			}
 
-				if ok {
-					fnArgs = fnArgs[1:] // strip self
-					fnEnv.Set(self.Value, receiverInstance)
-				}
+			return e.CallWrapperFunction(stmt, fn, params)
 
-			}
+		case *value.Enum:
+			member := e.enumMember(receiveryType, identifierName, identifierToken)
 
-			if len(fnArgs) > len(params) {
-				msg := fmt.Sprintf("Bad function arguments, expected %d, got %d", len(fnArgs), len(params))
-				return &value.Error{msg}
+			if e.Error(member) {
+				return member
 			}
 
-			// Args
-			for i, _arg := range fnArgs {
-				switch arg := _arg.(type) {
-				case *value.String:
-					fnEnv.Set(arg.Value, params[i])
+			fn, ok := member.(*value.WrapperFunction)
 
-				default:
-					msg := fmt.Sprintf("Unrecognized arguement type: %s", util.TypeOf(arg))
-					return &value.Error{msg}
-				}
+			if !ok {
+				msg := fmt.Sprintf("Variant %s carry no values, use %s without parentheses", member, member)
+				return &value.Error{Value: msg, Token: identifierToken}
 			}
 
-			return e.Eval(valFn.Body, fnEnv)
+			return e.CallWrapperFunction(stmt, fn, params)
 
-		case *value.Module:
-			valFn, ok := receiverInstance.(*value.Module).Value.(*value.Map[value.Value]).Map[identifierName]
+		case *value.Array, *value.String, *value.Map[value.Value], *value.Int, *value.Float:
+			method, ok := stdlib.MethodsOf(receiverInstance)[identifierName]
 
 			if !ok {
-				msg := fmt.Sprintf("Symbol %s is not found", identifier.(*value.String).Value)
-				return &value.Error{msg}
+				msg := fmt.Sprintf("Unknown method %s on %s", identifierName, receiverInstance.Type())
+				return &value.Error{Value: msg, Token: identifierToken}
 			}
 
-			fn, ok := valFn.(*value.WrapperFunction)
-
-			if !ok {
-				return result
+			fn := &value.WrapperFunction{
+				Name: identifierName,
+				Fn: func(varargs ...value.Value) value.Value {
+					return method(receiverInstance, varargs...)
+				},
 			}
 
-			return fn.Fn(params...)
+			return e.CallWrapperFunction(stmt, fn, params)
 
 		default:
 			msg := fmt.Sprintf("Unrecognized receiver type: %s", util.TypeOf(receiverInstance))
-			return &value.Error{msg}
+			return &value.Error{Value: msg, Token: stmt.Token}
 		}
 	}
 
-	valFn, ok := identifier.(*value.Function)
+	switch fn := identifier.(type) {
+	case *value.Function:
+		return e.CallFunction(stmt, fn, nil, params)
 
-	if !ok {
+	case *value.WrapperFunction:
+		return e.CallWrapperFunction(stmt, fn, params)
+
+	default:
 		msg := fmt.Sprintf("Identifier %s is not a function", identifierName)
-		return &value.Error{msg}
+		return &value.Error{Value: msg, Token: identifierToken}
+	}
+}
+
+// CallFunction bind the params in a new environment enclosed by the one the
+// function was defined in, so the function see its lexical scope rather
+// than the caller's, receiver is bound to `self` for methods
+func (e *Evaluator) CallFunction(stmt *ast.NodeFunctionCall, valFn *value.Function, receiver value.Value, params []value.Value) value.Value {
+	fnEnv := environment.NewWithParent(valFn.Env.(*environment.Environment))
+
+	// Start bind params to args
+	fnArgs := valFn.Args
+
+	if len(valFn.Args) > 0 {
+		self, ok := valFn.Args[0].(*value.Self)
+
+		if ok && receiver != nil {
+			fnArgs = fnArgs[1:] // strip self
+			fnEnv.Set(self.Value, receiver)
+		}
 	}
 
-	if len(valFn.Args) > len(params) {
-		msg := fmt.Sprintf("Bad function arguments, expected %d, got %d", len(valFn.Args), len(params))
-		return &value.Error{msg}
+	if len(fnArgs) > len(params) {
+		msg := fmt.Sprintf("Bad function arguments, expected %d, got %d", len(fnArgs), len(params))
+		return &value.Error{Value: msg, Token: stmt.Token}
 	}
 
-	for i, _arg := range valFn.Args {
+	// Args
+	for i, _arg := range fnArgs {
 		switch arg := _arg.(type) {
 		case *value.String:
+			if err := e.checkRequired(stmt.Token, valFn, arg.Value, params[i]); err != nil {
+				return err
+			}
+
 			fnEnv.Set(arg.Value, params[i])
 
 		default:
 			msg := fmt.Sprintf("Unrecognized arguement type: %s", util.TypeOf(arg))
-			return &value.Error{msg}
+			return &value.Error{Value: msg, Token: stmt.Token}
 		}
 	}
 
-	return e.Eval(valFn.Body, fnEnv)
+	// The body share the scope of the arguments so a local
+	// can't silently redeclare one
+	var result value.Value
+
+	if body, ok := valFn.Body.(*ast.NodeBlockStmt); ok {
+		result = e.evalStatements(body, fnEnv)
+	} else {
+		result = e.Eval(valFn.Body, fnEnv)
+	}
+
+	if ret, ok := result.(*value.Return); ok {
+		return ret.Value
+	}
+
+	return result
 }
 
-func (e *Evaluator) EvaluateFunctionStmt(stmt *ast.NodeFunctionStmt, env *environment.Environment) value.Value {
-	var ident string
-	var receiver string
-	var result value.Value = value.NULL
+func (e *Evaluator) CallWrapperFunction(stmt *ast.NodeFunctionCall, fn *value.WrapperFunction, params []value.Value) value.Value {
+	result := fn.Fn(params...)
 
-	switch stmt.Identifier.(type) {
-	case *ast.NodeIdentifier:
-		ident = stmt.Identifier.(*ast.NodeIdentifier).Name
+	// Native functions know nothing about the source, so point
+	// their errors at the call
+	if err, ok := result.(*value.Error); ok && err.Token.Type == "" {
+		err.Token = stmt.Token
+	}
 
-	case *ast.NodeBinaryExpr:
-		receiver = stmt.Identifier.(*ast.NodeBinaryExpr).Left.(*ast.NodeIdentifier).Name
-		ident = stmt.Identifier.(*ast.NodeBinaryExpr).Right.(*ast.NodeIdentifier).Name
+	return result
+}
 
-	default:
-		msg := fmt.Sprintf("Unrecognized function identifier type: %s", util.TypeOf(stmt.Identifier))
-		return &value.Error{msg}
+// EvalFunctionExpr create an anonymous function closing over env
+func (e *Evaluator) EvalFunctionExpr(stmt *ast.NodeFunctionExpr, env *environment.Environment) value.Value {
+	args, requires, err := e.functionArgs(stmt.Token, stmt.Arguements)
+
+	if err != nil {
+		return err
 	}
 
-	args := make([]value.Value, len(stmt.Arguements))
+	return &value.Function{Args: args, Body: stmt.Body, Env: env, Requires: requires}
+}
+
+// functionArgs convert the declared arguements into their runtime form,
+// a plain name or `self` which is only allowed first. The arguements
+// naming an interface are returned with it in requires
+func (e *Evaluator) functionArgs(tok token.Token, arguements []ast.Expr) ([]value.Value, map[string]string, *value.Error) {
+	args := make([]value.Value, len(arguements))
+	var requires map[string]string
 
-	for i, _arg := range stmt.Arguements {
+	for i, _arg := range arguements {
 		switch arg := _arg.(type) {
 		case *ast.NodeIdentifier:
-			args[i] = &value.String{arg.Name}
+			args[i] = &value.String{Value: arg.Name}
+
+		case *ast.NodeTypedArguement:
+			if requires == nil {
+				requires = make(map[string]string)
+			}
+
+			args[i] = &value.String{Value: arg.Arguement.Name}
+			requires[arg.Arguement.Name] = arg.Interface.Name
 
 		case *ast.NodeSelf:
 			if i != 0 {
 				msg := fmt.Sprintf("self arguement should be at position 0, detected position: %d", i)
-				return &value.Error{msg}
+				return nil, nil, &value.Error{Value: msg, Token: arg.Token}
 			}
 
-			args[i] = &value.Self{arg.Name}
+			args[i] = &value.Self{Value: arg.Name}
 
 		default:
 			msg := fmt.Sprintf("Unrecognized arguement type: %s", util.TypeOf(arg))
-			return &value.Error{msg}
+			return nil, nil, &value.Error{Value: msg, Token: tok}
 		}
 	}
 
-	valFn := &value.Function{Args: args, Body: stmt.Body}
+	return args, requires, nil
+}
+
+func (e *Evaluator) EvaluateFunctionStmt(stmt *ast.NodeFunctionStmt, env *environment.Environment) value.Value {
+	var ident string
+	var receiver string
+	var result value.Value = value.NULL
+
+	switch stmt.Identifier.(type) {
+	case *ast.NodeIdentifier:
+		ident = stmt.Identifier.(*ast.NodeIdentifier).Name
+
+	case *ast.NodeBinaryExpr:
+		receiver = stmt.Identifier.(*ast.NodeBinaryExpr).Left.(*ast.NodeIdentifier).Name
+		ident = stmt.Identifier.(*ast.NodeBinaryExpr).Right.(*ast.NodeIdentifier).Name
+
+	default:
+		msg := fmt.Sprintf("Unrecognized function identifier type: %s", util.TypeOf(stmt.Identifier))
+		return &value.Error{Value: msg, Token: stmt.Token}
+	}
+
+	args, requires, err := e.functionArgs(stmt.Token, stmt.Arguements)
+
+	if err != nil {
+		return err
+	}
+
+	valFn := &value.Function{Args: args, Body: stmt.Body, Env: env, Requires: requires}
 
 	if receiver != "" {
 		receiverVal, ok := env.Get(receiver)
 
 		if !ok {
 			msg := fmt.Sprintf("Symbol %s is not exists", ident)
-			return &value.Error{msg}
+			return &value.Error{Value: msg, Token: stmt.Token}
 		}
 
-		_struct, ok := receiverVal.(*value.Struct[value.Value])
+		structType, ok := receiverVal.(*value.StructType)
 
 		if !ok {
 			msg := fmt.Sprintf("Symbol %s is not a struct", ident)
-			return &value.Error{msg}
+			return &value.Error{Value: msg, Token: stmt.Token}
 		}
 
-		if util.InArray[string](_struct.Prop, ident) {
+		// A method of an embedded struct can be redeclared, it is then
+		// found first
+		if _, declared := structType.Methods[ident]; declared || structType.Field(ident) != nil {
 			msg := fmt.Sprintf("Symbol %s already exists", ident)
-			return &value.Error{msg}
+			return &value.Error{Value: msg, Token: stmt.Token}
 		}
 
 		// No need to set the value to the environment since
-		// the struct is a pointer
-		_struct.Prop = append(_struct.Prop, ident)
-		_struct.KeyVal.Map[ident] = valFn
+		// the struct type is a pointer
+		structType.Methods[ident] = valFn
 	} else {
-		if _, ok := env.Get(ident); ok {
+		if env.Declared(ident) {
 			msg := fmt.Sprintf("Symbol %s already exists", ident)
-			return &value.Error{msg}
+			return &value.Error{Value: msg, Token: stmt.Token}
 		}
 
 		env.Set(ident, valFn)
@@ -744,7 +956,7 @@ func (e *Evaluator) EvaluateIdentifier(stmt *ast.NodeIdentifier, env *environmen
 
 	if !ok {
 		msg := fmt.Sprintf("Symbol %s is not found", stmt.Name)
-		return &value.Error{msg}
+		return &value.Error{Value: msg, Token: stmt.Token}
 	}
 
 	return val
@@ -752,16 +964,19 @@ func (e *Evaluator) EvaluateIdentifier(stmt *ast.NodeIdentifier, env *environmen
 
 func (e *Evaluator) EvaluateLetStmt(stmt *ast.NodeLetStmt, env *environment.Environment) value.Value {
 	var result value.Value = value.NULL
-	ident := stmt.Identifier.(*ast.NodeIdentifier).Name
+	identifier := stmt.Identifier.(*ast.NodeIdentifier)
+	ident := identifier.Name
 
 	val := e.Eval(stmt.Value, env)
 	if e.Error(val) {
 		return val
 	}
 
-	if _, ok := env.Get(ident); ok {
+	// Only a redeclaration in the same scope is an error,
+	// an outer variable is shadowed
+	if env.Declared(ident) {
 		msg := fmt.Sprintf("Variable %s is already exists", ident)
-		return &value.Error{msg}
+		return &value.Error{Value: msg, Token: identifier.Token}
 	}
 
 	env.Set(ident, val)
@@ -770,28 +985,31 @@ func (e *Evaluator) EvaluateLetStmt(stmt *ast.NodeLetStmt, env *environment.Envi
 
 func (e *Evaluator) EvaluateConstStmt(stmt *ast.NodeConstStmt, env *environment.Environment) value.Value {
 	var result value.Value = value.NULL
-	ident := stmt.Identifier.(*ast.NodeIdentifier).Name
+	identifier := stmt.Identifier.(*ast.NodeIdentifier)
+	ident := identifier.Name
 
 	val := e.Eval(stmt.Value, env)
 	if e.Error(val) {
 		return val
 	}
 
-	if _, ok := env.Get(ident); ok {
+	if env.Declared(ident) {
 		msg := fmt.Sprintf("Constant %s already exists", ident)
-		return &value.Error{msg}
+		return &value.Error{Value: msg, Token: identifier.Token}
 	}
 
-	env.Set(ident, val)
+	if e.FreezeConst {
+		value.Freeze(val)
+	}
+
+	env.Define(ident, val, environment.KIND_CONST)
 	return result
 }
 
 func (e *Evaluator) EvaluateBinaryExpr(stmt *ast.NodeBinaryExpr, env *environment.Environment) value.Value {
-	var result value.Value = value.NULL
-
 	switch stmt.Operator {
 
-	case "+":
+	case "+", "-", "*", "/", "%", "**":
 		left := e.Eval(stmt.Left, env)
 		if e.Error(left) {
 			return left
@@ -802,24 +1020,12 @@ func (e *Evaluator) EvaluateBinaryExpr(stmt *ast.NodeBinaryExpr, env *environmen
 			return right
 		}
 
-		val := left.(*value.Int).Value + right.(*value.Int).Value
-		return &value.Int{val}
+		return e.EvalArithmetic(stmt.Operator, stmt.Token, left, right)
 
-	case "-":
-		left := e.Eval(stmt.Left, env)
-		if e.Error(left) {
-			return left
-		}
+	case "=", "+=", "-=", "*=", "/=", "%=":
+		return e.EvalAssignment(stmt, env)
 
-		right := e.Eval(stmt.Right, env)
-		if e.Error(right) {
-			return right
-		}
-
-		val := left.(*value.Int).Value - right.(*value.Int).Value
-		return &value.Int{val}
-
-	case "*":
+	case "<", ">", "<=", ">=":
 		left := e.Eval(stmt.Left, env)
 		if e.Error(left) {
 			return left
@@ -830,10 +1036,9 @@ func (e *Evaluator) EvaluateBinaryExpr(stmt *ast.NodeBinaryExpr, env *environmen
 			return right
 		}
 
-		val := left.(*value.Int).Value * right.(*value.Int).Value
-		return &value.Int{val}
+		return e.EvalComparison(stmt.Operator, stmt.Token, left, right)
 
-	case "/":
+	case "&", "|", "^", "<<", ">>":
 		left := e.Eval(stmt.Left, env)
 		if e.Error(left) {
 			return left
@@ -844,142 +1049,26 @@ func (e *Evaluator) EvaluateBinaryExpr(stmt *ast.NodeBinaryExpr, env *environmen
 			return right
 		}
 
-		val := left.(*value.Int).Value / right.(*value.Int).Value
-		return &value.Int{val}
+		return e.EvalBitwise(stmt.Operator, stmt.Token, left, right)
 
-	case "%":
+	case "&&":
+		// Short-circuit, the result is the operand that decided it
 		left := e.Eval(stmt.Left, env)
-		if e.Error(left) {
+		if e.Error(left) || !value.IsTruthy(left) {
 			return left
 		}
 
-		right := e.Eval(stmt.Right, env)
-		if e.Error(right) {
-			return right
-		}
-
-		val := left.(*value.Int).Value % right.(*value.Int).Value
-		return &value.Int{val}
-
-	case "=":
-		var ident value.Value
-
-		switch node := stmt.Left.(type) {
-		case *ast.NodeIdentifier:
-			ident = &value.String{node.Name}
-
-		case *ast.NodeBinaryExpr:
-			ident = e.Eval(node.Left, env)
-
-			if e.Error(ident) {
-				return ident
-			}
-
-		default:
-			msg := fmt.Sprintf("Unrecognized assignment type: %s", util.TypeOf(stmt.Left))
-			return &value.Error{msg}
-		}
-
-		switch node := stmt.Left.(type) {
-		case *ast.NodeIdentifier:
-			val := e.Eval(stmt.Right, env)
-
-			if e.Error(val) {
-				return val
-			}
-
-			realIdent := ident.(*value.String).Value
-
-			if _, ok := env.Get(realIdent); !ok {
-				msg := fmt.Sprintf("Variable %s is not found", realIdent)
-				return &value.Error{msg}
-			}
-
-			env.Assign(realIdent, val)
-			return val
-
-		case *ast.NodeBinaryExpr:
-			val := e.Eval(stmt.Right, env)
-
-			if e.Error(val) {
-				return val
-			}
-
-			receiver, ok := ident.(*value.Struct[value.Value])
+		return e.Eval(stmt.Right, env)
 
-			if !ok {
-				msg := fmt.Sprintf("Invalid receiver type: %s", util.TypeOf(receiver))
-				return &value.Error{msg}
-			}
-
-			right, ok := node.Right.(*ast.NodeIdentifier)
-
-			if !ok {
-				msg := fmt.Sprintf("Invalid identifier: %s", node.Right)
-				return &value.Error{msg}
-			}
-
-			ident = &value.String{Value: right.Name}
-
-			_, ok = receiver.Map[ident.(*value.String).Value]
-
-			if !ok {
-				msg := fmt.Sprintf("Symbol %s is not found", ident.(*value.String).Value)
-				return &value.Error{msg}
-			}
-
-			receiver.Map[ident.(*value.String).Value] = val
-			return result
-
-		default:
-			msg := fmt.Sprintf("Unrecognized assignment type: %s", util.TypeOf(stmt.Left))
-			return &value.Error{msg}
-		}
-
-	case "<":
+	case "||":
 		left := e.Eval(stmt.Left, env)
-		if e.Error(left) {
+		if e.Error(left) || value.IsTruthy(left) {
 			return left
 		}
 
-		right := e.Eval(stmt.Right, env)
-		if e.Error(right) {
-			return right
-		}
-
-		if util.TypeOf(left) != util.TypeOf(right) {
-			msg := fmt.Sprintf("Invalid operation %s < %s", left, right)
-			return &value.Error{msg}
-		}
-
-		switch left.(type) {
-
-		case *value.Int:
-			left, _ := left.(*value.Int)
-			right, _ := right.(*value.Int)
-
-			if left.Value < right.Value {
-				return value.TRUE
-			}
-
-			return value.FALSE
-
-		case *value.Float:
-			left, _ := left.(*value.Float)
-			right, _ := right.(*value.Float)
-
-			if left.Value < right.Value {
-				return value.TRUE
-			}
-
-			return value.FALSE
-
-		default:
-			msg := fmt.Sprintf("Invalid operation %s < %s", left, right)
-			return &value.Error{msg}
-		}
+		return e.Eval(stmt.Right, env)
 
-	case ">":
+	case "in":
 		left := e.Eval(stmt.Left, env)
 		if e.Error(left) {
 			return left
@@ -990,39 +1079,9 @@ func (e *Evaluator) EvaluateBinaryExpr(stmt *ast.NodeBinaryExpr, env *environmen
 			return right
 		}
 
-		if util.TypeOf(left) != util.TypeOf(right) {
-			msg := fmt.Sprintf("Invalid operation %s > %s", left, right)
-			return &value.Error{msg}
-		}
-
-		switch left.(type) {
-
-		case *value.Int:
-			left, _ := left.(*value.Int)
-			right, _ := right.(*value.Int)
-
-			if left.Value > right.Value {
-				return value.TRUE
-			}
+		return e.EvalIn(stmt.Token, left, right)
 
-			return value.FALSE
-
-		case *value.Float:
-			left, _ := left.(*value.Float)
-			right, _ := right.(*value.Float)
-
-			if left.Value > right.Value {
-				return value.TRUE
-			}
-
-			return value.FALSE
-
-		default:
-			msg := fmt.Sprintf("Invalid operation %s > %s", left, right)
-			return &value.Error{msg}
-		}
-
-	case "<=":
+	case "is":
 		left := e.Eval(stmt.Left, env)
 		if e.Error(left) {
 			return left
@@ -1033,49 +1092,9 @@ func (e *Evaluator) EvaluateBinaryExpr(stmt *ast.NodeBinaryExpr, env *environmen
 			return right
 		}
 
-		if util.TypeOf(left) != util.TypeOf(right) {
-			msg := fmt.Sprintf("Invalid operation %s <= %s", left, right)
-			return &value.Error{msg}
-		}
-
-		switch left.(type) {
-
-		case *value.Int:
-			left, _ := left.(*value.Int)
-			right, _ := right.(*value.Int)
-
-			if left.Value <= right.Value {
-				return value.TRUE
-			}
+		return e.EvalIs(stmt.Token, left, right)
 
-			return value.FALSE
-
-		case *value.Float:
-			_left := e.Eval(stmt.Left, env)
-			if e.Error(_left) {
-				return _left
-			}
-
-			_right := e.Eval(stmt.Right, env)
-			if e.Error(_right) {
-				return _right
-			}
-
-			left, _ := _left.(*value.Float)
-			right, _ := _right.(*value.Float)
-
-			if left.Value <= right.Value {
-				return value.TRUE
-			}
-
-			return value.FALSE
-
-		default:
-			msg := fmt.Sprintf("Invalid operation %s <= %s", left, right)
-			return &value.Error{msg}
-		}
-
-	case ">=":
+	case "==":
 		left := e.Eval(stmt.Left, env)
 		if e.Error(left) {
 			return left
@@ -1086,39 +1105,9 @@ func (e *Evaluator) EvaluateBinaryExpr(stmt *ast.NodeBinaryExpr, env *environmen
 			return right
 		}
 
-		if util.TypeOf(left) != util.TypeOf(right) {
-			msg := fmt.Sprintf("Invalid operation %s >= %s", left, right)
-			return &value.Error{msg}
-		}
+		return nativeBool(value.Equal(left, right))
 
-		switch left.(type) {
-
-		case *value.Int:
-			left, _ := left.(*value.Int)
-			right, _ := right.(*value.Int)
-
-			if left.Value >= right.Value {
-				return value.TRUE
-			}
-
-			return value.FALSE
-
-		case *value.Float:
-			left, _ := left.(*value.Float)
-			right, _ := right.(*value.Float)
-
-			if left.Value >= right.Value {
-				return value.TRUE
-			}
-
-			return value.FALSE
-
-		default:
-			msg := fmt.Sprintf("Invalid operation %s >= %s", left, right)
-			return &value.Error{msg}
-		}
-
-	case "==":
+	case "!=":
 		left := e.Eval(stmt.Left, env)
 		if e.Error(left) {
 			return left
@@ -1129,49 +1118,45 @@ func (e *Evaluator) EvaluateBinaryExpr(stmt *ast.NodeBinaryExpr, env *environmen
 			return right
 		}
 
-		if left == right {
-			return value.TRUE
-		}
-
-		return value.FALSE
+		return nativeBool(!value.Equal(left, right))
 
-	case "!=":
+	case "??":
+		// The right operand is only evaluated when the left one is null
 		left := e.Eval(stmt.Left, env)
-		if e.Error(left) {
+		if _, isNull := left.(*value.Null); !isNull {
 			return left
 		}
 
-		right := e.Eval(stmt.Right, env)
-		if e.Error(right) {
-			return right
-		}
-
-		if left != right {
-			return value.TRUE
-		}
-
-		return value.FALSE
+		return e.Eval(stmt.Right, env)
 
-	case ".":
+	case ".", "?.":
 		receiver := e.Eval(stmt.Left, env)
 		if e.Error(receiver) {
 			return receiver
 		}
 
-		right := stmt.Right.(*ast.NodeIdentifier).Name
-		var env *environment.Environment
+		field, ok := stmt.Right.(*ast.NodeIdentifier)
+		if !ok {
+			return &value.Error{Value: fmt.Sprintf("Expect a field name after `%s`", stmt.Operator), Token: stmt.Token}
+		}
+
+		right := field.Name
 
 		switch receiver.(type) {
 		case *value.Null:
-			return result
+			if stmt.Operator == "?." {
+				return value.NULL
+			}
+
+			msg := fmt.Sprintf("Null dereference: can't access field %s of null, use ?. to allow null", right)
+			return &value.Error{Value: msg, Token: stmt.Token}
 
 		case *value.Module:
-			env = receiver.(*value.Module).Value.(*environment.Environment)
-			val, ok := env.Get(right)
+			val, ok := receiver.(*value.Module).Value.(*value.KeyVal[value.Value]).Map[right]
 
 			if !ok {
 				msg := fmt.Sprintf("Symbol %s is not found", right)
-				return &value.Error{msg}
+				return &value.Error{Value: msg, Token: stmt.Token}
 			}
 
 			return val
@@ -1181,23 +1166,40 @@ func (e *Evaluator) EvaluateBinaryExpr(stmt *ast.NodeBinaryExpr, env *environmen
 
 			if !ok {
 				msg := fmt.Sprintf("Symbol %s is not found", right)
-				return &value.Error{msg}
+				return &value.Error{Value: msg, Token: stmt.Token}
 			}
 
 			return val
 
+		case *value.StructType:
+			// A function declared on the struct, eg: `User.new`
+			fn, ok := receiver.(*value.StructType).Method(right)
+
+			if !ok {
+				msg := fmt.Sprintf("Symbol %s is not found", right)
+				return &value.Error{Value: msg, Token: stmt.Token}
+			}
+
+			return fn
+
+		case *value.Enum:
+			return e.enumMember(receiver.(*value.Enum), right, stmt.Token)
+
+		case *value.EnumValue:
+			return e.enumField(receiver.(*value.EnumValue), right, stmt.Token)
+
 		case *value.Self:
 			self, _ := env.Get(receiver.(*value.Self).Value)
 			return self
 
 		default:
 			msg := fmt.Sprintf("Unknown receiverInstance type %s for dot operator", util.TypeOf(receiver))
-			return &value.Error{msg}
+			return &value.Error{Value: msg, Token: stmt.Token}
 		}
 
 	default:
 		msg := fmt.Sprintf("Unrecognized operator: %s", stmt.Operator)
-		return &value.Error{msg}
+		return &value.Error{Value: msg, Token: stmt.Token}
 	}
 
 }
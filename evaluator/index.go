@@ -0,0 +1,198 @@
+package evaluator
+
+import (
+	"fmt"
+	"kat/ast"
+	"kat/environment"
+	"kat/token"
+	"kat/util"
+	"kat/value"
+)
+
+// EvalIndexExpr evaluate `xs[i]` on arrays and strings, a negative index
+// count from the end so `xs[-1]` is the last element, and `m[key]` on maps.
+// Strings are indexed by rune and give a one rune string
+func (e *Evaluator) EvalIndexExpr(stmt *ast.NodeIndexExpr, env *environment.Environment) value.Value {
+	identifier := e.Eval(stmt.Identifier, env)
+
+	if e.Error(identifier) {
+		return identifier
+	}
+
+	idx := e.Eval(stmt.Index, env)
+
+	if e.Error(idx) {
+		return idx
+	}
+
+	switch node := identifier.(type) {
+	case *value.Array:
+		i, err := sequenceIndex("Array", idx, len(node.Value), stmt.Token)
+
+		if err != nil {
+			return err
+		}
+
+		return node.Value[i]
+
+	case *value.String:
+		runes := []rune(node.Value)
+		i, err := sequenceIndex("String", idx, len(runes), stmt.Token)
+
+		if err != nil {
+			return err
+		}
+
+		return &value.String{Value: string(runes[i])}
+
+	case *value.Map[value.Value]:
+		if err := mapKey(idx, stmt.Token); err != nil {
+			return err
+		}
+
+		val, ok := node.Get(idx)
+
+		if !ok {
+			msg := fmt.Sprintf("Map index %s is not found", idx)
+			return &value.Error{Value: msg, Token: stmt.Token}
+		}
+
+		return val
+
+	case *value.Null:
+		msg := "Null dereference: can't index null"
+		return &value.Error{Value: msg, Token: stmt.Token}
+
+	default:
+		msg := fmt.Sprintf("Unsupported index access on type %s", util.TypeOf(identifier))
+		return &value.Error{Value: msg, Token: stmt.Token}
+	}
+}
+
+// EvalSliceExpr evaluate `xs[start:end]` on arrays and strings, the result
+// is a new array or string holding the elements from start up to but not
+// including end. Missing bounds default to the start and the end, negative
+// ones count from the end
+func (e *Evaluator) EvalSliceExpr(stmt *ast.NodeSliceExpr, env *environment.Environment) value.Value {
+	identifier := e.Eval(stmt.Identifier, env)
+
+	if e.Error(identifier) {
+		return identifier
+	}
+
+	var length int
+
+	switch node := identifier.(type) {
+	case *value.Array:
+		length = len(node.Value)
+
+	case *value.String:
+		length = len([]rune(node.Value))
+
+	case *value.Null:
+		msg := "Null dereference: can't slice null"
+		return &value.Error{Value: msg, Token: stmt.Token}
+
+	default:
+		msg := fmt.Sprintf("Unsupported slice on type %s", util.TypeOf(identifier))
+		return &value.Error{Value: msg, Token: stmt.Token}
+	}
+
+	start, err := e.sliceBound(stmt.Start, 0, length, stmt.Token, env)
+
+	if err != nil {
+		return err
+	}
+
+	end, err := e.sliceBound(stmt.End, length, length, stmt.Token, env)
+
+	if err != nil {
+		return err
+	}
+
+	if start > end {
+		msg := fmt.Sprintf("Slice bounds %d:%d out of range, start is after end", start, end)
+		return &value.Error{Value: msg, Token: stmt.Token}
+	}
+
+	switch node := identifier.(type) {
+	case *value.Array:
+		elems := make([]value.Value, end-start)
+		copy(elems, node.Value[start:end])
+		return &value.Array{Value: elems}
+
+	default:
+		runes := []rune(identifier.(*value.String).Value)
+		return &value.String{Value: string(runes[start:end])}
+	}
+}
+
+// sliceBound evaluate one bound of a slice, it return def when the bound
+// is left out. A bound can be equal to the length, `xs[len(xs):]` is empty
+func (e *Evaluator) sliceBound(bound ast.Expr, def int, length int, tok token.Token, env *environment.Environment) (int, value.Value) {
+	if bound == nil {
+		return def, nil
+	}
+
+	val := e.Eval(bound, env)
+
+	if e.Error(val) {
+		return 0, val
+	}
+
+	i, ok := val.(*value.Int)
+
+	if !ok {
+		msg := fmt.Sprintf("Slice bound is not an int, got: %s", val.Type())
+		return 0, &value.Error{Value: msg, Token: tok}
+	}
+
+	n := i.Value
+
+	if n < 0 {
+		n += int64(length)
+	}
+
+	if n < 0 || n > int64(length) {
+		msg := fmt.Sprintf("Slice bound %d out of range, length is %d", i.Value, length)
+		return 0, &value.Error{Value: msg, Token: tok}
+	}
+
+	return int(n), nil
+}
+
+// mapKey check key can be hashed, arrays, maps and structs can't be used
+// as a map key
+func mapKey(key value.Value, tok token.Token) value.Value {
+	if _, err := value.Hash(key); err != nil {
+		msg := fmt.Sprintf("Invalid map key: %s", err)
+		return &value.Error{Value: msg, Token: tok}
+	}
+
+	return nil
+}
+
+// sequenceIndex check idx is an int in range of a sequence of the given
+// length and turn a negative one into an offset from the start, kind is
+// the name used in the errors
+func sequenceIndex(kind string, idx value.Value, length int, tok token.Token) (int, value.Value) {
+	i, ok := idx.(*value.Int)
+
+	if !ok {
+		msg := fmt.Sprintf("%s index is not an int", kind)
+		return 0, &value.Error{Value: msg, Token: tok}
+	}
+
+	n := i.Value
+
+	if n < 0 {
+		n += int64(length)
+	}
+
+	if n < 0 || n >= int64(length) {
+		msg := fmt.Sprintf("%s index %d out of range, length is %d", kind, i.Value, length)
+		return 0, &value.Error{Value: msg, Token: tok}
+	}
+
+	return int(n), nil
+}
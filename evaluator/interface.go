@@ -0,0 +1,183 @@
+package evaluator
+
+import (
+	"fmt"
+	"kat/ast"
+	"kat/environment"
+	"kat/token"
+	"kat/util"
+	"kat/value"
+	"strings"
+)
+
+func (e *Evaluator) EvalInterfaceStmt(stmt *ast.NodeInterfaceStmt, env *environment.Environment) value.Value {
+	identifier, ok := stmt.Identifier.(*ast.NodeIdentifier)
+
+	if !ok {
+		msg := fmt.Sprintf("Invalid identifier: %s", stmt.Identifier)
+		return &value.Error{Value: msg, Token: stmt.Token}
+	}
+
+	if env.Declared(identifier.Name) {
+		msg := fmt.Sprintf("Symbol %s already exists", identifier.Name)
+		return &value.Error{Value: msg, Token: stmt.Token}
+	}
+
+	iface := &value.Interface{Name: identifier.Name}
+
+	for _, m := range stmt.Methods {
+		method := &value.InterfaceMethod{Name: m.Name}
+
+		for _, a := range m.Arguements {
+			switch arg := a.(type) {
+			case *ast.NodeIdentifier:
+				method.Args = append(method.Args, arg.Name)
+
+			case *ast.NodeSelf:
+				method.Args = append(method.Args, arg.Name)
+
+			default:
+				msg := fmt.Sprintf("Invalid method arguement: %s", a)
+				return &value.Error{Value: msg, Token: m.Token}
+			}
+		}
+
+		iface.Methods = append(iface.Methods, method)
+	}
+
+	env.Set(identifier.Name, iface)
+	return value.NULL
+}
+
+// EvalImplStmt evaluate `impl Interface for Struct`, it is an error when
+// the methods declared on the struct so far don't cover the interface
+func (e *Evaluator) EvalImplStmt(stmt *ast.NodeImplStmt, env *environment.Environment) value.Value {
+	iface, err := e.lookupInterface(stmt.Interface.(*ast.NodeIdentifier).Name, stmt.Token, env)
+
+	if err != nil {
+		return err
+	}
+
+	name := stmt.Struct.(*ast.NodeIdentifier).Name
+	declared, ok := env.Get(name)
+	structType, isStruct := declared.(*value.StructType)
+
+	if !ok || !isStruct {
+		msg := fmt.Sprintf("Struct %s is not found", name)
+		return &value.Error{Value: msg, Token: stmt.Token}
+	}
+
+	if missing := missingMethods(iface, structType); len(missing) > 0 {
+		msg := fmt.Sprintf("%s does not implement %s, missing: %s", name, iface.Name, strings.Join(missing, ", "))
+		return &value.Error{Value: msg, Token: stmt.Token}
+	}
+
+	return value.NULL
+}
+
+// EvalIs evaluate `value is Type`, an interface is satisfied by any struct
+// having its methods while a struct or an enum only by its own values
+func (e *Evaluator) EvalIs(tok token.Token, left value.Value, right value.Value) value.Value {
+	switch right := right.(type) {
+	case *value.Interface:
+		return nativeBool(len(missingMethods(right, structTypeOf(left))) == 0)
+
+	case *value.StructType:
+		instance, ok := left.(*value.Struct[value.Value])
+		return nativeBool(ok && instance.StructType == right)
+
+	case *value.Enum:
+		instance, ok := left.(*value.EnumValue)
+		return nativeBool(ok && instance.Enum == right)
+
+	default:
+		msg := fmt.Sprintf("Expect an interface, a struct or an enum after is, got: %s", util.TypeOf(right))
+		return &value.Error{Value: msg, Token: tok}
+	}
+}
+
+// checkRequired report an error when the arguement name of fn must
+// implement an interface and val does not
+func (e *Evaluator) checkRequired(tok token.Token, fn *value.Function, name string, val value.Value) value.Value {
+	ifaceName, ok := fn.Requires[name]
+
+	if !ok {
+		return nil
+	}
+
+	env := fn.Env.(*environment.Environment)
+	iface, err := e.lookupInterface(ifaceName, tok, env)
+
+	if err != nil {
+		return err
+	}
+
+	if missing := missingMethods(iface, structTypeOf(val)); len(missing) > 0 {
+		msg := fmt.Sprintf("Arguement %s must implement %s, %s is missing: %s", name, iface.Name, typeName(val), strings.Join(missing, ", "))
+		return &value.Error{Value: msg, Token: tok}
+	}
+
+	return nil
+}
+
+func (e *Evaluator) lookupInterface(name string, tok token.Token, env *environment.Environment) (*value.Interface, value.Value) {
+	declared, ok := env.Get(name)
+	iface, isInterface := declared.(*value.Interface)
+
+	if !ok || !isInterface {
+		msg := fmt.Sprintf("Interface %s is not found", name)
+		return nil, &value.Error{Value: msg, Token: tok}
+	}
+
+	return iface, nil
+}
+
+// missingMethods list the methods of iface that structType lacks, a
+// method is found when the struct, or a struct it embed, declare it with
+// the same number of arguements and with `self` first when the interface
+// has it. A nil structType, the type of values other than structs, has no
+// methods
+func missingMethods(iface *value.Interface, structType *value.StructType) []string {
+	missing := make([]string, 0)
+
+	for _, method := range iface.Methods {
+		var fn *value.Function
+		ok := false
+
+		if structType != nil {
+			fn, ok = structType.Method(method.Name)
+		}
+
+		// A method taking `self` can't be satisfied by a static function
+		needSelf := len(method.Args) > 0 && method.Args[0] == "self"
+
+		if !ok || len(fn.Args) != len(method.Args) || (needSelf && !isMethod(fn)) {
+			missing = append(missing, method.String())
+		}
+	}
+
+	return missing
+}
+
+// structTypeOf return the type of a struct value, nil for other values
+func structTypeOf(val value.Value) *value.StructType {
+	if instance, ok := val.(*value.Struct[value.Value]); ok {
+		return instance.StructType
+	}
+
+	return nil
+}
+
+// typeName is the name of the type of val shown in errors, the name of
+// its struct or enum when it has one
+func typeName(val value.Value) string {
+	switch val := val.(type) {
+	case *value.Struct[value.Value]:
+		return val.Name
+
+	case *value.EnumValue:
+		return val.Enum.Name
+	}
+
+	return string(val.Type())
+}
@@ -0,0 +1,238 @@
+package evaluator
+
+import (
+	"fmt"
+	"kat/ast"
+	"kat/environment"
+	"kat/value"
+)
+
+// EvalMatchExpr evaluate `match subject { ... }`, the arms are tried in
+// order and the first one whose pattern match and whose guard is truthy
+// is evaluated, with the bindings of the pattern in its own scope. It is
+// an error when no arm match
+func (e *Evaluator) EvalMatchExpr(stmt *ast.NodeMatchExpr, env *environment.Environment) value.Value {
+	subject := e.Eval(stmt.Subject, env)
+
+	if e.Error(subject) {
+		return subject
+	}
+
+	for _, arm := range stmt.Arms {
+		bindings := make(map[string]value.Value)
+		matched, err := e.matchPattern(arm.Pattern, subject, bindings, env)
+
+		if err != nil {
+			return err
+		}
+
+		if !matched {
+			continue
+		}
+
+		armEnv := environment.NewWithParent(env)
+
+		for name, val := range bindings {
+			armEnv.Set(name, val)
+		}
+
+		if arm.Guard != nil {
+			guard := e.Eval(arm.Guard, armEnv)
+
+			if e.Error(guard) {
+				return guard
+			}
+
+			if !value.IsTruthy(guard) {
+				continue
+			}
+		}
+
+		if body, ok := arm.Body.(*ast.NodeBlockStmt); ok {
+			return e.evalStatements(body, armEnv)
+		}
+
+		return e.Eval(arm.Body, armEnv)
+	}
+
+	msg := fmt.Sprintf("No match arm for value %s", subject)
+	return &value.Error{Value: msg, Token: stmt.Token}
+}
+
+// matchPattern report whether subject match pattern, the variables it bind
+// are added to bindings. The error is set when the pattern is invalid,
+// like a struct pattern naming an unknown field
+func (e *Evaluator) matchPattern(pattern ast.Pat, subject value.Value, bindings map[string]value.Value, env *environment.Environment) (bool, value.Value) {
+	switch pattern := pattern.(type) {
+	case *ast.NodeWildcardPattern:
+		return true, nil
+
+	case *ast.NodeBindingPattern:
+		bindings[pattern.Name] = subject
+		return true, nil
+
+	case *ast.NodeLiteralPattern:
+		literal := e.Eval(pattern.Value, env)
+
+		if e.Error(literal) {
+			return false, literal
+		}
+
+		return value.Equal(literal, subject), nil
+
+	case *ast.NodeAlternativePattern:
+		for _, alternative := range pattern.Alternatives {
+			// Only keep the bindings of the alternative that matched
+			alternativeBindings := make(map[string]value.Value)
+			matched, err := e.matchPattern(alternative, subject, alternativeBindings, env)
+
+			if err != nil || matched {
+				for name, val := range alternativeBindings {
+					bindings[name] = val
+				}
+
+				return matched, err
+			}
+		}
+
+		return false, nil
+
+	case *ast.NodeArrayPattern:
+		return e.matchArrayPattern(pattern, subject, bindings, env)
+
+	case *ast.NodeMapPattern:
+		return e.matchMapPattern(pattern, subject, bindings, env)
+
+	case *ast.NodeStructPattern:
+		return e.matchStructPattern(pattern, subject, bindings, env)
+
+	case *ast.NodeEnumPattern:
+		return e.matchEnumPattern(pattern, subject, bindings, env)
+
+	default:
+		msg := fmt.Sprintf("Unrecognized pattern type: %T", pattern)
+		return false, &value.Error{Value: msg}
+	}
+}
+
+func (e *Evaluator) matchArrayPattern(pattern *ast.NodeArrayPattern, subject value.Value, bindings map[string]value.Value, env *environment.Environment) (bool, value.Value) {
+	arr, ok := subject.(*value.Array)
+
+	if !ok {
+		return false, nil
+	}
+
+	if len(arr.Value) < len(pattern.Elements) || (pattern.Rest == nil && len(arr.Value) != len(pattern.Elements)) {
+		return false, nil
+	}
+
+	for i, elem := range pattern.Elements {
+		if matched, err := e.matchPattern(elem, arr.Value[i], bindings, env); err != nil || !matched {
+			return false, err
+		}
+	}
+
+	if pattern.Rest == nil {
+		return true, nil
+	}
+
+	rest := make([]value.Value, len(arr.Value)-len(pattern.Elements))
+	copy(rest, arr.Value[len(pattern.Elements):])
+
+	return e.matchPattern(pattern.Rest, &value.Array{Value: rest}, bindings, env)
+}
+
+func (e *Evaluator) matchMapPattern(pattern *ast.NodeMapPattern, subject value.Value, bindings map[string]value.Value, env *environment.Environment) (bool, value.Value) {
+	valMap, ok := subject.(*value.Map[value.Value])
+
+	if !ok {
+		return false, nil
+	}
+
+	for i, k := range pattern.Keys {
+		key := e.Eval(k, env)
+
+		if e.Error(key) {
+			return false, key
+		}
+
+		val, ok := valMap.Get(key)
+
+		if !ok {
+			return false, nil
+		}
+
+		if matched, err := e.matchPattern(pattern.Values[i], val, bindings, env); err != nil || !matched {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func (e *Evaluator) matchStructPattern(pattern *ast.NodeStructPattern, subject value.Value, bindings map[string]value.Value, env *environment.Environment) (bool, value.Value) {
+	declared, ok := env.Get(pattern.Name)
+	structType, isStruct := declared.(*value.StructType)
+
+	if !ok || !isStruct {
+		msg := fmt.Sprintf("Struct %s is not found", pattern.Name)
+		return false, &value.Error{Value: msg, Token: pattern.Token}
+	}
+
+	for _, field := range pattern.Fields {
+		if structType.Field(field) == nil {
+			msg := fmt.Sprintf("Unknown field %s on %s", field, pattern.Name)
+			return false, &value.Error{Value: msg, Token: pattern.Token}
+		}
+	}
+
+	instance, ok := subject.(*value.Struct[value.Value])
+
+	if !ok || instance.StructType != structType {
+		return false, nil
+	}
+
+	for i, field := range pattern.Fields {
+		if matched, err := e.matchPattern(pattern.Values[i], instance.Map[field], bindings, env); err != nil || !matched {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func (e *Evaluator) matchEnumPattern(pattern *ast.NodeEnumPattern, subject value.Value, bindings map[string]value.Value, env *environment.Environment) (bool, value.Value) {
+	declared, ok := env.Get(pattern.Enum)
+	enum, isEnum := declared.(*value.Enum)
+
+	if !ok || !isEnum {
+		msg := fmt.Sprintf("Enum %s is not found", pattern.Enum)
+		return false, &value.Error{Value: msg, Token: pattern.Token}
+	}
+
+	variant := enum.Variant(pattern.Variant)
+
+	if variant == nil {
+		msg := fmt.Sprintf("Unknown variant %s on %s", pattern.Variant, pattern.Enum)
+		return false, &value.Error{Value: msg, Token: pattern.Token}
+	}
+
+	if pattern.Values != nil && len(pattern.Values) != len(variant.Fields) {
+		msg := fmt.Sprintf("Bad variant values for %s.%s, expected %d, got %d", pattern.Enum, pattern.Variant, len(variant.Fields), len(pattern.Values))
+		return false, &value.Error{Value: msg, Token: pattern.Token}
+	}
+
+	instance, ok := subject.(*value.EnumValue)
+
+	if !ok || instance.Enum != enum || instance.Variant.Name != pattern.Variant {
+		return false, nil
+	}
+
+	for i, val := range pattern.Values {
+		if matched, err := e.matchPattern(val, instance.Values[i], bindings, env); err != nil || !matched {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
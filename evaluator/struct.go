@@ -0,0 +1,137 @@
+package evaluator
+
+import (
+	"fmt"
+	"kat/ast"
+	"kat/environment"
+	"kat/value"
+)
+
+// EvalStructStmt declare a struct type, a field naming another struct
+// embed it: its fields are copied and its methods are found through the
+// embedded type
+func (e *Evaluator) EvalStructStmt(stmt *ast.NodeStructStmt, env *environment.Environment) value.Value {
+	var result value.Value = value.NULL
+	identifier, ok := stmt.Identifier.(*ast.NodeIdentifier)
+
+	if !ok {
+		msg := fmt.Sprintf("Invalid identifier: %s", stmt.Identifier)
+		return &value.Error{Value: msg, Token: stmt.Token}
+	}
+
+	if env.Declared(identifier.Name) {
+		msg := fmt.Sprintf("Symbol %s already exists", identifier.Name)
+		return &value.Error{Value: msg, Token: stmt.Token}
+	}
+
+	structType := &value.StructType{Name: identifier.Name, Methods: make(map[string]*value.Function)}
+
+	for _, f := range stmt.Fields {
+		fields := []*value.StructField{{Name: f.Name, Default: f.Default, Env: env, Required: f.Required}}
+
+		if embedded, ok := e.embeddedStruct(f, env); ok {
+			structType.Embeds = append(structType.Embeds, embedded)
+			fields = embedded.Fields
+		}
+
+		for _, field := range fields {
+			if structType.Field(field.Name) != nil {
+				msg := fmt.Sprintf("Field %s is already declared in %s", field.Name, identifier.Name)
+				return &value.Error{Value: msg, Token: f.Token}
+			}
+
+			structType.Fields = append(structType.Fields, field)
+		}
+	}
+
+	env.Set(identifier.Name, structType)
+	return result
+}
+
+// embeddedStruct return the struct a plain field name, if any
+func (e *Evaluator) embeddedStruct(field *ast.NodeStructField, env *environment.Environment) (*value.StructType, bool) {
+	if field.Default != nil || field.Required {
+		return nil, false
+	}
+
+	declared, ok := env.Get(field.Name)
+
+	if !ok {
+		return nil, false
+	}
+
+	embedded, ok := declared.(*value.StructType)
+	return embedded, ok
+}
+
+// EvalStructExpr evaluate `Name{field: value, ...}`, the fields left out
+// get their default or null, leaving out a required field is an error
+func (e *Evaluator) EvalStructExpr(stmt *ast.NodeStructExpr, env *environment.Environment) value.Value {
+	ident, ok := stmt.Name.(*ast.NodeIdentifier)
+
+	if !ok {
+		msg := fmt.Sprintf("Invalid identifier: %s", stmt.Name)
+		return &value.Error{Value: msg, Token: stmt.Token}
+	}
+
+	declared, ok := env.Get(ident.Name)
+	structType, isStruct := declared.(*value.StructType)
+
+	if !ok || !isStruct {
+		msg := fmt.Sprintf("Struct %s is not found", ident.Name)
+		return &value.Error{Value: msg, Token: stmt.Token}
+	}
+
+	keyMap := e.Eval(stmt.Values, env)
+
+	if e.Error(keyMap) {
+		return keyMap
+	}
+
+	props := keyMap.(*value.Map[value.Value])
+
+	for _, entry := range props.Entries {
+		k, ok := entry.Key.(*value.String)
+
+		if !ok || structType.Field(k.Value) == nil {
+			msg := fmt.Sprintf("Unknown field %s on %s", entry.Key, ident.Name)
+			return &value.Error{Value: msg, Token: stmt.Token}
+		}
+	}
+
+	valKeyVal := make(map[string]value.Value)
+
+	for _, field := range structType.Fields {
+		val, ok := props.Get(&value.String{Value: field.Name})
+
+		if !ok && field.Required {
+			msg := fmt.Sprintf("Missing required field %s in %s", field.Name, ident.Name)
+			return &value.Error{Value: msg, Token: stmt.Token}
+		}
+
+		if !ok && field.Default != nil {
+			val = e.Eval(field.Default, field.Env.(*environment.Environment))
+
+			if e.Error(val) {
+				return val
+			}
+		} else if !ok {
+			val = value.NULL
+		}
+
+		valKeyVal[field.Name] = val
+	}
+
+	return &value.Struct[value.Value]{StructType: structType, KeyVal: &value.KeyVal[value.Value]{Map: valKeyVal}}
+}
+
+// isMethod report whether fn take `self`, the other functions declared on
+// a struct are static
+func isMethod(fn *value.Function) bool {
+	if len(fn.Args) == 0 {
+		return false
+	}
+
+	_, ok := fn.Args[0].(*value.Self)
+	return ok
+}
@@ -1,11 +1,15 @@
 package lexer
 
 import (
+	"fmt"
+	"kat/diagnostic"
 	"kat/token"
 	"slices"
+	"strings"
 )
 
 type Lexer struct {
+	File   string
 	Col    int
 	Line   int
 	Offset int
@@ -16,8 +20,13 @@ func New(input []byte) *Lexer {
 	return &Lexer{Col: 0, Line: 0, Offset: 0, Input: input}
 }
 
+func NewWithFile(file string, input []byte) *Lexer {
+	return &Lexer{File: file, Col: 0, Line: 0, Offset: 0, Input: input}
+}
+
 func (l *Lexer) MakeToken(col int, val string, tokenType token.TokenType) token.Token {
 	return token.Token{
+		File:  l.File,
 		Row:   l.Line,
 		Col:   col - l.Offset,
 		Value: val,
@@ -37,6 +46,10 @@ func (l *Lexer) NextToken() token.Token {
 			col := l.Col
 			l.NextChar()
 			t = l.MakeToken(col, string(l.Input[col:col+2]), token.PLUSPLUS)
+		} else if l.PeekChar() == '=' {
+			col := l.Col
+			l.NextChar()
+			t = l.MakeToken(col, string(l.Input[col:col+2]), token.PLUSEQUAL)
 		} else {
 			t = l.MakeToken(l.Col, string(ch), token.PLUS)
 		}
@@ -46,6 +59,10 @@ func (l *Lexer) NextToken() token.Token {
 			col := l.Col
 			l.NextChar()
 			t = l.MakeToken(col, string(l.Input[col:col+2]), token.MINUSMINUS)
+		} else if l.PeekChar() == '=' {
+			col := l.Col
+			l.NextChar()
+			t = l.MakeToken(col, string(l.Input[col:col+2]), token.MINUSEQUAL)
 		} else {
 			t = l.MakeToken(l.Col, string(ch), token.MINUS)
 		}
@@ -55,6 +72,10 @@ func (l *Lexer) NextToken() token.Token {
 			col := l.Col
 			l.NextChar()
 			t = l.MakeToken(col, string(l.Input[col:col+2]), token.EQUALEQUAL)
+		} else if l.PeekChar() == '>' {
+			col := l.Col
+			l.NextChar()
+			t = l.MakeToken(col, string(l.Input[col:col+2]), token.FATARROW)
 		} else {
 			t = l.MakeToken(l.Col, string(ch), token.EQUAL)
 		}
@@ -68,11 +89,39 @@ func (l *Lexer) NextToken() token.Token {
 			t = l.MakeToken(l.Col, string(ch), token.BANG)
 		}
 
+	case '&':
+		if l.PeekChar() == '&' {
+			col := l.Col
+			l.NextChar()
+			t = l.MakeToken(col, string(l.Input[col:col+2]), token.AND)
+		} else {
+			t = l.MakeToken(l.Col, string(ch), token.BITAND)
+		}
+
+	case '|':
+		if l.PeekChar() == '|' {
+			col := l.Col
+			l.NextChar()
+			t = l.MakeToken(col, string(l.Input[col:col+2]), token.OR)
+		} else {
+			t = l.MakeToken(l.Col, string(ch), token.BITOR)
+		}
+
+	case '^':
+		t = l.MakeToken(l.Col, string(ch), token.BITXOR)
+
+	case '~':
+		t = l.MakeToken(l.Col, string(ch), token.TILDE)
+
 	case '<':
 		if l.PeekChar() == '=' {
 			col := l.Col
 			l.NextChar()
 			t = l.MakeToken(col, string(l.Input[col:col+2]), token.LESSEQUAL)
+		} else if l.PeekChar() == '<' {
+			col := l.Col
+			l.NextChar()
+			t = l.MakeToken(col, string(l.Input[col:col+2]), token.SHIFTLEFT)
 		} else {
 			t = l.MakeToken(l.Col, string(ch), token.LESS)
 		}
@@ -82,31 +131,44 @@ func (l *Lexer) NextToken() token.Token {
 			col := l.Col
 			l.NextChar()
 			t = l.MakeToken(col, string(l.Input[col:col+2]), token.GREATEREQUAL)
+		} else if l.PeekChar() == '>' {
+			col := l.Col
+			l.NextChar()
+			t = l.MakeToken(col, string(l.Input[col:col+2]), token.SHIFTRIGHT)
 		} else {
 			t = l.MakeToken(l.Col, string(ch), token.GREATER)
 		}
 
 	case '*':
-		t = l.MakeToken(l.Col, string(ch), token.MULTIPLY)
-
-	case '/':
-		if l.PeekChar() == '/' {
+		if l.PeekChar() == '*' {
+			col := l.Col
 			l.NextChar()
+			t = l.MakeToken(col, string(l.Input[col:col+2]), token.POWER)
+		} else if l.PeekChar() == '=' {
+			col := l.Col
 			l.NextChar()
+			t = l.MakeToken(col, string(l.Input[col:col+2]), token.MULTIPLYEQUAL)
+		} else {
+			t = l.MakeToken(l.Col, string(ch), token.MULTIPLY)
+		}
 
-			for l.PeekToken(1).Type != token.EOL &&
-				l.PeekToken(1).Type != token.EOF {
-				t = l.NextToken()
-			}
-
-			l.NextToken()     // consume the EOL or EOF
-			t = l.NextToken() // advance to next token
+	case '/':
+		if l.PeekChar() == '=' {
+			col := l.Col
+			l.NextChar()
+			t = l.MakeToken(col, string(l.Input[col:col+2]), token.DIVIDEEQUAL)
 		} else {
 			t = l.MakeToken(l.Col, string(ch), token.DIVIDE)
 		}
 
 	case '%':
-		t = l.MakeToken(l.Col, string(ch), token.MODULO)
+		if l.PeekChar() == '=' {
+			col := l.Col
+			l.NextChar()
+			t = l.MakeToken(col, string(l.Input[col:col+2]), token.MODULOEQUAL)
+		} else {
+			t = l.MakeToken(l.Col, string(ch), token.MODULO)
+		}
 
 	case '[':
 		t = l.MakeToken(l.Col, string(ch), token.LBRACKET)
@@ -127,12 +189,28 @@ func (l *Lexer) NextToken() token.Token {
 		t = l.MakeToken(l.Col, string(ch), token.RPAREN)
 
 	case '?':
-		t = l.MakeToken(l.Col, string(ch), token.QUESTION)
+		if l.PeekChar() == '?' {
+			col := l.Col
+			l.NextChar()
+			t = l.MakeToken(col, string(l.Input[col:col+2]), token.COALESCE)
+		} else if l.PeekChar() == '.' && !l.IsDigit(l.PeekCharAt(2)) {
+			// `cond ?.5 : 1` is still a conditional
+			col := l.Col
+			l.NextChar()
+			t = l.MakeToken(col, string(l.Input[col:col+2]), token.QUESTIONDOT)
+		} else {
+			t = l.MakeToken(l.Col, string(ch), token.QUESTION)
+		}
 
 	case '"':
 		col := l.Col
-		str := l.MakeString()
-		t = l.MakeToken(col, string(str), token.STRING)
+		str, ok := l.MakeString()
+
+		if ok {
+			t = l.MakeToken(col, string(str), token.STRING)
+		} else {
+			t = l.MakeToken(col, string(str), token.INVALID)
+		}
 
 	case ':':
 		t = l.MakeToken(l.Col, string(ch), token.COLON)
@@ -144,7 +222,18 @@ func (l *Lexer) NextToken() token.Token {
 		t = l.MakeToken(l.Col, string(ch), token.SEMICOLON)
 
 	case '.':
-		t = l.MakeToken(l.Col, string(ch), token.DOT)
+		if l.PeekChar() == '.' && l.PeekCharAt(2) == '<' {
+			col := l.Col
+			l.NextChar()
+			l.NextChar()
+			t = l.MakeToken(col, string(l.Input[col:col+3]), token.DOTDOTLESS)
+		} else if l.PeekChar() == '.' {
+			col := l.Col
+			l.NextChar()
+			t = l.MakeToken(col, string(l.Input[col:col+2]), token.DOTDOT)
+		} else {
+			t = l.MakeToken(l.Col, string(ch), token.DOT)
+		}
 
 	case '\n':
 		t = l.MakeToken(l.Col, "\\n", token.EOL)
@@ -167,7 +256,7 @@ func (l *Lexer) NextToken() token.Token {
 
 			t = l.MakeToken(col, string(dig), tok)
 
-		} else if l.IsChar(ch) {
+		} else if l.IsChar(ch) || ch == '_' {
 			col := l.Col
 			unknown := l.MakeIdentifier()
 			symbol := string(unknown)
@@ -185,14 +274,14 @@ func (l *Lexer) NextToken() token.Token {
 }
 
 func (l *Lexer) PeekToken(count int) token.Token {
-	start := l.Col
+	start, line, offset := l.Col, l.Line, l.Offset
 	var t token.Token
 
 	for i := 0; i < count; i++ {
 		t = l.NextToken()
 	}
 
-	l.Col = start
+	l.Col, l.Line, l.Offset = start, line, offset
 
 	return t
 }
@@ -210,8 +299,12 @@ func (l *Lexer) NextChar() {
 }
 
 func (l *Lexer) PeekChar() byte {
-	if l.Col+1 < len(l.Input) {
-		return l.Input[l.Col+1]
+	return l.PeekCharAt(1)
+}
+
+func (l *Lexer) PeekCharAt(offset int) byte {
+	if l.Col+offset < len(l.Input) {
+		return l.Input[l.Col+offset]
 	}
 
 	return 0
@@ -225,10 +318,6 @@ func (l *Lexer) IsDigit(ch byte) bool {
 	return ch >= '0' && ch <= '9'
 }
 
-func (l *Lexer) IsDouble(ch byte) bool {
-	return ch >= '0' && ch <= '9' || ch == '.'
-}
-
 func (l *Lexer) IsAlphaNum(ch byte) bool {
 	return l.IsChar(ch) || l.IsDigit(ch) || ch == '_'
 }
@@ -238,34 +327,66 @@ func (l *Lexer) IsWhitespace(ch byte) bool {
 }
 
 func (l *Lexer) IsEndOfString() bool {
-	return l.Char() == '"' || l.Char() == 0
+	return l.Char() == '"' || l.Char() == '\n' || l.Char() == 0
 }
 
+// SkipWhitespace skip blanks and `//` comments, the newline ending
+// a comment is kept since it terminate the statement
 func (l *Lexer) SkipWhitespace() {
-	for l.Col < len(l.Input) && l.IsWhitespace(l.Input[l.Col]) {
-		l.NextChar()
+	for l.Col < len(l.Input) {
+		if l.IsWhitespace(l.Char()) {
+			l.NextChar()
+		} else if l.Char() == '/' && l.PeekChar() == '/' {
+			for l.Char() != '\n' && l.Char() != 0 {
+				l.NextChar()
+			}
+		} else {
+			break
+		}
 	}
 }
 
-func (l *Lexer) MakeString() []byte {
+// MakeString return the string literal including its quotes, ok is false
+// when the string is not terminated before the end of the line
+func (l *Lexer) MakeString() ([]byte, bool) {
 	start := l.Col
 	l.NextChar() // skip first `"` so when we find next `"` it mark end of string
 
 	for !l.IsEndOfString() {
+		if l.Char() == '\\' && l.PeekChar() != '\n' && l.PeekChar() != 0 {
+			l.NextChar() // skip the escaped character
+		}
+
 		l.NextChar()
 	}
 
+	if l.Char() != '"' {
+		end := l.Col
+		l.Col-- // leave the newline or eof for the next token
+		return l.Input[start:end], false
+	}
+
 	end := l.Col
-	return l.Input[start : end+1]
+	return l.Input[start : end+1], true
 }
 
+// MakeDigit return an integer or a double literal, a `.` belong to the
+// number only when a digit follow it so `0..10` is a range
 func (l *Lexer) MakeDigit() []byte {
 	start := l.Col
 
-	for l.IsDouble(l.Char()) {
+	for l.IsDigit(l.Char()) {
 		l.NextChar()
 	}
 
+	if l.Char() == '.' && l.IsDigit(l.PeekChar()) {
+		l.NextChar() // consume `.`
+
+		for l.IsDigit(l.Char()) {
+			l.NextChar()
+		}
+	}
+
 	end := l.Col
 	l.Col--
 
@@ -295,3 +416,14 @@ func (l *Lexer) MakeInvalid() []byte {
 	l.Col--
 	return l.Input[start:end]
 }
+
+// Diagnose describe why a token is invalid
+func Diagnose(tok token.Token) *diagnostic.Diagnostic {
+	if strings.HasPrefix(tok.Value, "\"") {
+		return diagnostic.FromToken(tok, diagnostic.SEVERITY_ERROR, diagnostic.CODE_UNTERMINATED_STRING,
+			"Unterminated string literal")
+	}
+
+	return diagnostic.FromToken(tok, diagnostic.SEVERITY_ERROR, diagnostic.CODE_INVALID_TOKEN,
+		fmt.Sprintf("Invalid token `%s`", tok.Value))
+}
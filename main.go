@@ -2,29 +2,273 @@ package main
 
 import (
 	"fmt"
+	"io"
+	"kat/ast"
+	"kat/diagnostic"
 	"kat/environment"
 	"kat/evaluator"
 	"kat/lexer"
 	"kat/parser"
-	"kat/util"
+	"kat/repl"
+	"kat/stdlib"
+	"kat/token"
 	"kat/value"
+	"os"
+	"strings"
+)
+
+const usage = `Usage:
+  kat                          start the interactive repl
+  kat run <file.kat> [args...] run a script, use - to read it from stdin
+  kat <file.kat> [args...]     shorthand for kat run, the options follow the file
+  kat -e '<source>' [args...]  run a one-liner
+  kat check <file.kat>         lex and parse only, report syntax problems
+  kat tokens <file.kat>        dump the tokens produced by the lexer
+  kat ast <file.kat>           dump the syntax tree produced by the parser
+  kat help                     show this message
+
+Options for run and -e, given before the script:
+  --overflow=wrap|error        integer overflow wrap around (default) or fail
+  --freeze-const               make arrays, maps and structs bound to a const immutable
+`
+
+// Evaluator options set from the command line
+var (
+	overflow    = evaluator.OVERFLOW_WRAP
+	freezeConst = false
+)
+
+// Process exit codes
+const (
+	exitOK      = 0 // script finished without error
+	exitRuntime = 1 // evaluation returned an error
+	exitUsage   = 2 // bad command line
+	exitNoInput = 3 // script could not be read
+	exitSyntax  = 4 // script has syntax errors
 )
 
 func main() {
-	source := util.ReadFile("./doc/stdlib.kat")
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	if len(args) == 0 {
+		repl.Start(os.Stdin, os.Stdout)
+		return exitOK
+	}
+
+	command, rest := args[0], args[1:]
+
+	switch command {
+	case "run", "-e":
+		rest, err := parseOptions(rest)
+
+		if err != nil {
+			return usageError(err.Error())
+		}
+
+		if command == "-e" {
+			if len(rest) == 0 {
+				return usageError("-e requires a source string")
+			}
+
+			return runSource("<eval>", []byte(rest[0]), rest[1:])
+		}
+
+		if len(rest) == 0 {
+			return usageError("run requires a file name")
+		}
+
+		return runFile(rest[0], rest[1:])
+
+	case "check", "tokens", "ast":
+		if len(rest) != 1 {
+			return usageError(fmt.Sprintf("%s requires exactly one file name", command))
+		}
+
+		source, err := readSource(rest[0])
+
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitNoInput
+		}
+
+		name := sourceName(rest[0])
+
+		switch command {
+		case "check":
+			return checkSource(name, source)
+		case "tokens":
+			return dumpTokens(name, source)
+		default:
+			return dumpAst(name, source)
+		}
+
+	case "repl":
+		repl.Start(os.Stdin, os.Stdout)
+		return exitOK
+
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return exitOK
+
+	default:
+		if strings.HasSuffix(command, ".kat") {
+			// The options can follow the file, `kat file.kat --overflow=error`
+			rest, err := parseOptions(rest)
+
+			if err != nil {
+				return usageError(err.Error())
+			}
+
+			return runFile(command, rest)
+		}
+
+		return usageError(fmt.Sprintf("unknown command %s", command))
+	}
+}
+
+// parseOptions consume the leading `--option=value` arguments and return
+// the remaining ones
+func parseOptions(args []string) ([]string, error) {
+	for len(args) > 0 && strings.HasPrefix(args[0], "--") {
+		name, val, _ := strings.Cut(args[0], "=")
+
+		switch name {
+		case "--freeze-const":
+			freezeConst = true
+
+		case "--overflow":
+			switch val {
+			case "wrap":
+				overflow = evaluator.OVERFLOW_WRAP
+			case "error":
+				overflow = evaluator.OVERFLOW_ERROR
+			default:
+				return nil, fmt.Errorf("invalid --overflow value `%s`, expected wrap or error", val)
+			}
+
+		default:
+			return nil, fmt.Errorf("unknown option %s", name)
+		}
+
+		args = args[1:]
+	}
+
+	return args, nil
+}
+
+func usageError(msg string) int {
+	fmt.Fprintf(os.Stderr, "kat: %s\n\n%s", msg, usage)
+	return exitUsage
+}
 
-	l := lexer.New(source)
-	p := parser.New(l)
+// readSource read the script from the given path, `-` mean stdin
+func readSource(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+
+	return os.ReadFile(path)
+}
+
+// sourceName is the file name shown in diagnostics
+func sourceName(path string) string {
+	if path == "-" {
+		return "<stdin>"
+	}
+
+	return path
+}
+
+func report(d *diagnostic.Diagnostic, source []byte) {
+	fmt.Fprint(os.Stderr, d.Render(source, diagnostic.UseColor(os.Stderr)))
+}
+
+func runFile(path string, args []string) int {
+	source, err := readSource(path)
 
-	program := p.ParseProgram()
-	//fmt.Println(program.String())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitNoInput
+	}
+
+	return runSource(sourceName(path), source, args)
+}
+
+func runSource(name string, source []byte, args []string) int {
+	stdlib.SetArgs(append([]string{name}, args...))
+
+	program, ok := parse(name, source)
+
+	if !ok {
+		return exitSyntax
+	}
 
 	e := evaluator.New(program)
+	e.Overflow = overflow
+	e.FreezeConst = freezeConst
 	env := environment.New()
 	res := e.Eval(program, env)
 
 	if err, ok := res.(*value.Error); ok {
-		fmt.Println(err)
+		report(err.Diagnostic(), source)
+		return exitRuntime
+	}
+
+	return exitOK
+}
+
+// parse the source and report every syntax error, ok is false when
+// there was any
+func parse(name string, source []byte) (*ast.NodeProgram, bool) {
+	p := parser.New(lexer.NewWithFile(name, source))
+	program, errs := p.ParseProgram()
+
+	for _, err := range errs {
+		report(err.Diagnostic(), source)
+	}
+
+	return program, len(errs) == 0
+}
+
+func checkSource(name string, source []byte) int {
+	if _, ok := parse(name, source); !ok {
+		return exitSyntax
+	}
+
+	return exitOK
+}
+
+func dumpTokens(name string, source []byte) int {
+	l := lexer.NewWithFile(name, source)
+	status := exitOK
+
+	for {
+		t := l.NextToken()
+
+		fmt.Println(t.Dump())
+
+		if t.Type == token.INVALID {
+			report(lexer.Diagnose(t), source)
+			status = exitSyntax
+		}
+
+		if t.Type == token.EOF {
+			break
+		}
+	}
+
+	return status
+}
+
+func dumpAst(name string, source []byte) int {
+	program, ok := parse(name, source)
+
+	if !ok {
+		return exitSyntax
 	}
 
+	fmt.Println(program.String())
+	return exitOK
 }
@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"fmt"
+	"kat/ast"
+	"kat/diagnostic"
+	"kat/lexer"
+	"kat/token"
+)
+
+type ParseError struct {
+	Token    token.Token     // the offending token
+	Expected token.TokenType // the token that was expected, empty if none in particular
+	Code     diagnostic.Code
+	Message  string
+}
+
+func (pe *ParseError) Diagnostic() *diagnostic.Diagnostic {
+	return diagnostic.FromToken(pe.Token, diagnostic.SEVERITY_ERROR, pe.Code, pe.Message)
+}
+
+func (pe *ParseError) Error() string {
+	return pe.Diagnostic().Error()
+}
+
+// bailout unwind the parser up to the nearest statement boundary
+// once an error has been recorded
+type bailout struct{}
+
+// Errorf record an error at the given token and abandon the current statement,
+// an invalid token is reported with the lexer diagnostic instead
+func (p *Parser) Errorf(tok token.Token, expected token.TokenType, format string, args ...any) {
+	p.ErrorCode(tok, expected, diagnostic.CODE_UNEXPECTED_TOKEN, format, args...)
+}
+
+func (p *Parser) ErrorCode(tok token.Token, expected token.TokenType, code diagnostic.Code, format string, args ...any) {
+	err := &ParseError{
+		Token:    tok,
+		Expected: expected,
+		Code:     code,
+		Message:  fmt.Sprintf(format, args...),
+	}
+
+	if tok.Type == token.INVALID {
+		d := lexer.Diagnose(tok)
+		err.Code, err.Message = d.Code, d.Message
+	}
+
+	p.Errors = append(p.Errors, err)
+	panic(bailout{})
+}
+
+// ParseStatementRecover parse a statement, if it fail the error is
+// already recorded so skip to the next statement boundary and return nil
+func (p *Parser) ParseStatementRecover() (stmt ast.Stmt) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(bailout); !ok {
+				panic(r)
+			}
+
+			p.synchronize()
+			stmt = nil
+		}
+	}()
+
+	return p.ParseStatement()
+}
+
+// synchronize skip tokens until the end of the statement, that is an
+// EOL, the `}` closing the enclosing block or the end of file
+func (p *Parser) synchronize() {
+	if p.CurrentToken().Type == token.EOL {
+		return
+	}
+
+	for p.PeekToken().Type != token.EOL &&
+		p.PeekToken().Type != token.RBRACE &&
+		p.PeekToken().Type != token.EOF {
+		p.ConsumeToken()
+	}
+}
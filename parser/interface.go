@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"kat/ast"
+	"kat/token"
+)
+
+// ParseNodeInterface parse `interface Name { method(self, x), ... }`, the
+// methods are separated by commas or new lines
+func (p *Parser) ParseNodeInterface() ast.Stmt {
+	currentToken := p.CurrentToken()
+
+	identifier := p.parseIdentifierExpr(token.Precedence.EXPR)
+
+	p.skipEOL()
+
+	p.ExpectToken(token.LBRACE) // consume `{`
+
+	methods := make([]*ast.NodeInterfaceMethod, 0)
+	seen := make(map[string]bool)
+
+	for p.PeekToken().Type != token.RBRACE && p.PeekToken().Type != token.EOF {
+		p.skipEOL()
+
+		if p.PeekToken().Type == token.RBRACE {
+			break
+		}
+
+		name := p.ExpectToken(token.IDENTIFIER)
+
+		if seen[name.Value] {
+			p.Errorf(name, "", "Method %s is already declared in %s", name.Value, identifier.Name)
+		}
+
+		seen[name.Value] = true
+
+		p.ExpectToken(token.LPAREN)
+		arguements := p.ParseNodeFunctionArguement()
+		p.ExpectToken(token.RPAREN)
+
+		methods = append(methods, &ast.NodeInterfaceMethod{Token: name, Name: name.Value, Arguements: arguements})
+
+		if p.PeekToken().Type == token.COMMA {
+			p.ConsumeToken() // consume `,`
+		}
+	}
+
+	p.ExpectToken(token.RBRACE) // consume `}`
+	p.declare(identifier.Name, false)
+
+	return &ast.NodeInterfaceStmt{
+		Token:      currentToken,
+		Identifier: identifier,
+		Methods:    methods,
+	}
+}
+
+// ParseNodeImpl parse `impl Interface for Struct`
+func (p *Parser) ParseNodeImpl() ast.Stmt {
+	currentToken := p.CurrentToken()
+
+	iface := p.parseIdentifierExpr(token.Precedence.EXPR)
+	p.ExpectToken(token.FOR)
+	_struct := p.parseIdentifierExpr(token.Precedence.EXPR)
+
+	return &ast.NodeImplStmt{
+		Token:     currentToken,
+		Interface: iface,
+		Struct:    _struct,
+	}
+}
@@ -0,0 +1,261 @@
+package parser
+
+import (
+	"kat/ast"
+	"kat/token"
+)
+
+// ParseMatchExpr parse `match subject { pattern => body, ... }`, an arm
+// can have a guard, `pattern if cond => body`, and its body is a block or
+// a single expression. Arms are separated by commas or new lines
+func (p *Parser) ParseMatchExpr() ast.Expr {
+	node := &ast.NodeMatchExpr{Token: p.CurrentToken()}
+	node.Subject = p.ParseExpression(token.Precedence.LOWEST + 1)
+
+	p.ExpectToken(token.LBRACE)
+	p.skipEOL()
+
+	for p.PeekToken().Type != token.RBRACE && p.PeekToken().Type != token.EOF {
+		node.Arms = append(node.Arms, p.parseMatchArm())
+
+		if p.PeekToken().Type == token.COMMA {
+			p.ExpectToken(token.COMMA)
+		}
+
+		p.skipEOL()
+	}
+
+	p.ExpectToken(token.RBRACE)
+
+	return node
+}
+
+func (p *Parser) parseMatchArm() *ast.NodeMatchArm {
+	// The bindings of the pattern are only visible in the arm
+	p.pushScope()
+	defer p.popScope()
+
+	arm := &ast.NodeMatchArm{Token: p.PeekToken(), Pattern: p.parsePattern()}
+
+	if p.PeekToken().Type == token.IF {
+		p.ExpectToken(token.IF)
+		arm.Guard = p.ParseExpression(token.Precedence.LOWEST)
+	}
+
+	p.ExpectToken(token.FATARROW)
+	p.skipEOL()
+
+	if p.PeekToken().Type == token.LBRACE {
+		arm.Body = p.parseBlockStmt()
+	} else {
+		arm.Body = p.ParseExpressionStatement()
+	}
+
+	return arm
+}
+
+// parsePattern parse a pattern with its `|` alternatives
+func (p *Parser) parsePattern() ast.Pat {
+	first := p.parseSinglePattern()
+
+	if p.PeekToken().Type != token.BITOR {
+		return first
+	}
+
+	node := &ast.NodeAlternativePattern{Token: p.PeekToken(), Alternatives: []ast.Pat{first}}
+
+	for p.PeekToken().Type == token.BITOR {
+		p.ExpectToken(token.BITOR)
+		node.Alternatives = append(node.Alternatives, p.parseSinglePattern())
+	}
+
+	return node
+}
+
+func (p *Parser) parseSinglePattern() ast.Pat {
+	tok := p.PeekToken()
+
+	switch tok.Type {
+	case token.INTEGER, token.DOUBLE, token.STRING, token.TRUE, token.FALSE, token.NULL, token.MINUS:
+		return &ast.NodeLiteralPattern{Token: tok, Value: p.ParseExpression(token.Precedence.PREFIX)}
+
+	case token.IDENTIFIER:
+		p.ExpectToken(token.IDENTIFIER)
+
+		if p.PeekToken().Type == token.LBRACE {
+			return p.parseStructPattern(tok)
+		}
+
+		if p.PeekToken().Type == token.DOT {
+			return p.parseEnumPattern(tok)
+		}
+
+		return p.bindingPattern(tok)
+
+	case token.LBRACKET:
+		return p.parseArrayPattern()
+
+	case token.LBRACE:
+		return p.parseMapPattern()
+
+	case token.LPAREN:
+		p.ExpectToken(token.LPAREN)
+		pattern := p.parsePattern()
+		p.ExpectToken(token.RPAREN)
+
+		return pattern
+	}
+
+	p.Errorf(tok, "", "Expect a pattern, got: %s `%s`", tok.Type, tok.Value)
+	return nil
+}
+
+// bindingPattern turn an identifier into a binding, `_` match anything
+// without binding it
+func (p *Parser) bindingPattern(tok token.Token) ast.Pat {
+	if tok.Value == "_" {
+		return &ast.NodeWildcardPattern{Token: tok}
+	}
+
+	p.declare(tok.Value, false)
+
+	return &ast.NodeBindingPattern{Token: tok, Name: tok.Value}
+}
+
+// parseArrayPattern parse `[a, b]`, the last element can be `..rest` to
+// bind the remaining elements or `..` to ignore them
+func (p *Parser) parseArrayPattern() ast.Pat {
+	node := &ast.NodeArrayPattern{Token: p.ExpectToken(token.LBRACKET)}
+
+	for p.PeekToken().Type != token.RBRACKET {
+		if p.PeekToken().Type == token.DOTDOT {
+			rest := p.ExpectToken(token.DOTDOT)
+
+			if p.PeekToken().Type == token.IDENTIFIER {
+				node.Rest = p.bindingPattern(p.ExpectToken(token.IDENTIFIER))
+			} else {
+				node.Rest = &ast.NodeWildcardPattern{Token: rest}
+			}
+
+			break
+		}
+
+		node.Elements = append(node.Elements, p.parsePattern())
+
+		if p.PeekToken().Type != token.RBRACKET {
+			p.ExpectToken(token.COMMA)
+		}
+	}
+
+	p.ExpectToken(token.RBRACKET)
+
+	return node
+}
+
+// parseMapPattern parse `{key: pattern, ...}`, a bare identifier key is a
+// string and `{name}` is short for `{name: name}`. The map can hold more
+// keys than the pattern list
+func (p *Parser) parseMapPattern() ast.Pat {
+	node := &ast.NodeMapPattern{Token: p.ExpectToken(token.LBRACE)}
+
+	p.parsePatternEntries(func(tok token.Token) {
+		var key ast.Expr
+
+		switch tok.Type {
+		case token.IDENTIFIER:
+			p.ExpectToken(token.IDENTIFIER)
+			key = &ast.NodeString{Token: tok, Value: tok.Value}
+
+		case token.STRING, token.INTEGER, token.DOUBLE, token.TRUE, token.FALSE, token.MINUS:
+			key = p.ParseExpression(token.Precedence.PREFIX)
+
+		default:
+			p.Errorf(tok, "", "Expect a map key, got: %s `%s`", tok.Type, tok.Value)
+		}
+
+		node.Keys = append(node.Keys, key)
+		node.Values = append(node.Values, p.parseEntryPattern(tok))
+	})
+
+	return node
+}
+
+// parseStructPattern parse `Name{field: pattern, ...}` where name is the
+// already consumed token, `{field}` is short for `{field: field}`
+func (p *Parser) parseStructPattern(name token.Token) ast.Pat {
+	node := &ast.NodeStructPattern{Token: name, Name: name.Value}
+
+	p.ExpectToken(token.LBRACE)
+
+	p.parsePatternEntries(func(tok token.Token) {
+		field := p.ExpectToken(token.IDENTIFIER)
+
+		node.Fields = append(node.Fields, field.Value)
+		node.Values = append(node.Values, p.parseEntryPattern(field))
+	})
+
+	return node
+}
+
+// parseEnumPattern parse `Enum.Variant` and `Enum.Variant(patterns)`
+// where enum is the already consumed token
+func (p *Parser) parseEnumPattern(enum token.Token) ast.Pat {
+	p.ExpectToken(token.DOT)
+	variant := p.ExpectToken(token.IDENTIFIER)
+
+	node := &ast.NodeEnumPattern{Token: enum, Enum: enum.Value, Variant: variant.Value}
+
+	if p.PeekToken().Type != token.LPAREN {
+		return node
+	}
+
+	p.ExpectToken(token.LPAREN)
+	node.Values = make([]ast.Pat, 0)
+
+	for p.PeekToken().Type != token.RPAREN {
+		node.Values = append(node.Values, p.parsePattern())
+
+		if p.PeekToken().Type != token.RPAREN {
+			p.ExpectToken(token.COMMA)
+		}
+	}
+
+	p.ExpectToken(token.RPAREN)
+
+	return node
+}
+
+// parsePatternEntries call entry for each `key: pattern` up to the closing
+// `}`, with the first token of the entry
+func (p *Parser) parsePatternEntries(entry func(tok token.Token)) {
+	p.skipEOL()
+
+	for p.PeekToken().Type != token.RBRACE {
+		entry(p.PeekToken())
+
+		if p.PeekToken().Type == token.COMMA {
+			p.ExpectToken(token.COMMA)
+		}
+
+		p.skipEOL()
+	}
+
+	p.ExpectToken(token.RBRACE)
+}
+
+// parseEntryPattern parse the pattern after a key, the key itself bind the
+// value when there is no `:`
+func (p *Parser) parseEntryPattern(key token.Token) ast.Pat {
+	if p.PeekToken().Type != token.COLON {
+		if key.Type != token.IDENTIFIER {
+			p.Errorf(p.PeekToken(), token.COLON, "Expect `:` after the key %s", key.Value)
+		}
+
+		return p.bindingPattern(key)
+	}
+
+	p.ExpectToken(token.COLON)
+	p.skipEOL()
+
+	return p.parsePattern()
+}
@@ -16,9 +16,10 @@ package parser
 
 import (
 	"kat/ast"
+	"kat/diagnostic"
 	"kat/lexer"
 	"kat/token"
-	"log"
+	"kat/util"
 	"strconv"
 )
 
@@ -33,24 +34,35 @@ type Parser struct {
 	PrefixFunctions    map[token.TokenType]PrefixParselet
 	InfixFunctions     map[token.TokenType]InfixParselet
 	StatementFunctions map[token.TokenType]StatementParselet
+	Errors             []*ParseError
+	Loops              []string // labels of the enclosing loops, empty for an unlabeled one
+	Label              string   // label waiting for the loop it precede
+	Scopes             []scope  // declarations of the enclosing blocks, innermost last
 }
 
 func New(lex *lexer.Lexer) *Parser {
 	p := &Parser{
 		Lex:                lex,
+		Errors:             make([]*ParseError, 0),
 		PrefixFunctions:    make(map[token.TokenType]PrefixParselet),
 		InfixFunctions:     make(map[token.TokenType]InfixParselet),
 		StatementFunctions: make(map[token.TokenType]StatementParselet),
+		Scopes:             []scope{{}},
 	}
 
 	// Regisgter statement functions
 	p.StatementFunctions[token.CONST] = p.ParseConstDecl
 	p.StatementFunctions[token.STRUCT] = p.ParseNodeStruct
+	p.StatementFunctions[token.ENUM] = p.ParseNodeEnum
+	p.StatementFunctions[token.INTERFACE] = p.ParseNodeInterface
+	p.StatementFunctions[token.IMPL] = p.ParseNodeImpl
 	p.StatementFunctions[token.FUNCTION] = p.ParseNodeFunction
 	p.StatementFunctions[token.LET] = p.ParseLetDecl
 	p.StatementFunctions[token.IF] = p.ParseIfStmt
 	p.StatementFunctions[token.FOR] = p.parseForStmt
 	p.StatementFunctions[token.RETURN] = p.parseReturnStmt
+	p.StatementFunctions[token.BREAK] = p.parseBreakStmt
+	p.StatementFunctions[token.CONTINUE] = p.parseContinueStmt
 
 	// Register Prefix functions
 	p.PrefixFunctions[token.SELF] = p.ParseSelf
@@ -58,8 +70,10 @@ func New(lex *lexer.Lexer) *Parser {
 	p.PrefixFunctions[token.DOUBLE] = p.ParseNodeDouble
 	p.PrefixFunctions[token.TRUE] = p.ParseNodeBoolean
 	p.PrefixFunctions[token.FALSE] = p.ParseNodeBoolean
+	p.PrefixFunctions[token.NULL] = p.ParseNodeNull
 	p.PrefixFunctions[token.MINUS] = p.ParsePrefixExpr
 	p.PrefixFunctions[token.BANG] = p.ParsePrefixExpr
+	p.PrefixFunctions[token.TILDE] = p.ParsePrefixExpr
 	p.PrefixFunctions[token.STRING] = p.ParseNodeString
 	p.PrefixFunctions[token.IDENTIFIER] = p.ParseIdentifier
 	p.PrefixFunctions[token.LBRACKET] = p.ParseArrayDecl
@@ -67,6 +81,10 @@ func New(lex *lexer.Lexer) *Parser {
 	p.PrefixFunctions[token.MINUSMINUS] = p.ParsePrefixExpr
 	p.PrefixFunctions[token.PLUSPLUS] = p.ParsePrefixExpr
 	p.PrefixFunctions[token.IMPORT] = p.ParseImportDecl
+	p.PrefixFunctions[token.FUNCTION] = p.ParseFunctionExpr
+	p.PrefixFunctions[token.IF] = p.ParseIfExpr
+	p.PrefixFunctions[token.MATCH] = p.ParseMatchExpr
+	p.PrefixFunctions[token.LPAREN] = p.ParseGroupedExpr
 
 	// Register Infix functions
 	p.InfixFunctions[token.PLUS] = p.ParseBinaryExpr
@@ -74,12 +92,23 @@ func New(lex *lexer.Lexer) *Parser {
 	p.InfixFunctions[token.MULTIPLY] = p.ParseBinaryExpr
 	p.InfixFunctions[token.DIVIDE] = p.ParseBinaryExpr
 	p.InfixFunctions[token.MODULO] = p.ParseBinaryExpr
+	p.InfixFunctions[token.POWER] = p.ParsePowerExpr
+	p.InfixFunctions[token.BITAND] = p.ParseBinaryExpr
+	p.InfixFunctions[token.BITOR] = p.ParseBinaryExpr
+	p.InfixFunctions[token.BITXOR] = p.ParseBinaryExpr
+	p.InfixFunctions[token.SHIFTLEFT] = p.ParseBinaryExpr
+	p.InfixFunctions[token.SHIFTRIGHT] = p.ParseBinaryExpr
 	p.InfixFunctions[token.QUESTION] = p.ParseConditionExpr
 	p.InfixFunctions[token.LESS] = p.ParseBinaryExpr
 	p.InfixFunctions[token.LESSEQUAL] = p.ParseBinaryExpr
 	p.InfixFunctions[token.GREATER] = p.ParseBinaryExpr
 	p.InfixFunctions[token.GREATEREQUAL] = p.ParseBinaryExpr
-	p.InfixFunctions[token.EQUAL] = p.ParseBinaryExpr
+	p.InfixFunctions[token.EQUAL] = p.ParseAssignmentExpr
+	p.InfixFunctions[token.PLUSEQUAL] = p.ParseAssignmentExpr
+	p.InfixFunctions[token.MINUSEQUAL] = p.ParseAssignmentExpr
+	p.InfixFunctions[token.MULTIPLYEQUAL] = p.ParseAssignmentExpr
+	p.InfixFunctions[token.DIVIDEEQUAL] = p.ParseAssignmentExpr
+	p.InfixFunctions[token.MODULOEQUAL] = p.ParseAssignmentExpr
 	p.InfixFunctions[token.LPAREN] = p.ParseFunctionCall
 	p.InfixFunctions[token.LBRACKET] = p.ParseIndexExpr
 	p.InfixFunctions[token.LBRACE] = p.ParseStructExpr
@@ -88,6 +117,14 @@ func New(lex *lexer.Lexer) *Parser {
 	p.InfixFunctions[token.EQUALEQUAL] = p.ParseBinaryExpr
 	p.InfixFunctions[token.NOTEQUAL] = p.ParseBinaryExpr
 	p.InfixFunctions[token.DOT] = p.ParseBinaryExpr
+	p.InfixFunctions[token.QUESTIONDOT] = p.ParseBinaryExpr
+	p.InfixFunctions[token.COALESCE] = p.ParseBinaryExpr
+	p.InfixFunctions[token.IN] = p.ParseBinaryExpr
+	p.InfixFunctions[token.IS] = p.ParseBinaryExpr
+	p.InfixFunctions[token.AND] = p.ParseLogicalExpr
+	p.InfixFunctions[token.OR] = p.ParseLogicalExpr
+	p.InfixFunctions[token.DOTDOT] = p.ParseRangeExpr
+	p.InfixFunctions[token.DOTDOTLESS] = p.ParseRangeExpr
 
 	p.NextToken = p.Lex.NextToken()
 	p.Token = p.NextToken
@@ -103,12 +140,9 @@ func (p *Parser) ConsumeToken() token.Token {
 
 func (p *Parser) ExpectToken(tok token.TokenType) token.Token {
 	if p.NextToken.Type != tok {
-		log.Fatalf("Expect next token of type: %s `%s`, got: %s `%s` at line: %d, column:%d\n",
+		p.Errorf(p.NextToken, tok, "Expect next token of type: %s `%s`, got: %s `%s`",
 			tok, tok.Str(), p.NextToken.Type, p.NextToken.Value,
-			p.NextToken.Row+1, p.NextToken.Col+1,
 		)
-
-		return token.Token{}
 	}
 
 	return p.ConsumeToken()
@@ -136,20 +170,26 @@ func (*Parser) GetOperatorPrecedence(tok token.Token) int {
 	return token.GetPrecedence(tok)
 }
 
-func (p *Parser) ParseProgram() *ast.NodeProgram {
+// ParseProgram parse the whole input, a statement that fail to parse is
+// skipped and the parsing continue with the next one, so every error
+// in the input is returned
+func (p *Parser) ParseProgram() (*ast.NodeProgram, []*ParseError) {
 	program := &ast.NodeProgram{}
 
-	for p.CurrentToken().Type != token.EOF {
-		program.Body = append(program.Body, p.ParseStatement())
-
+	for {
 		p.skipEOL()
 
 		if p.PeekToken().Type == token.EOF {
 			p.ConsumeToken()
+			break
+		}
+
+		if stmt := p.ParseStatementRecover(); stmt != nil {
+			program.Body = append(program.Body, stmt)
 		}
 	}
 
-	return program
+	return program, p.Errors
 }
 
 func (p *Parser) ParseExpression(currentPrecedence int) ast.Expr {
@@ -157,9 +197,8 @@ func (p *Parser) ParseExpression(currentPrecedence int) ast.Expr {
 	prefixFunction, ok := p.PrefixFunctions[p.CurrentToken().Type]
 
 	if !ok {
-		log.Fatalf("Could not parse prefix token: %s, value: `%s` at line: %d, column: %d",
-			p.CurrentToken().Type, p.CurrentToken().Value, p.CurrentToken().Row+1,
-			p.CurrentToken().Col+1,
+		p.Errorf(p.CurrentToken(), "", "Could not parse prefix token: %s, value: `%s`",
+			p.CurrentToken().Type, p.CurrentToken().Value,
 		)
 	}
 
@@ -171,9 +210,8 @@ func (p *Parser) ParseExpression(currentPrecedence int) ast.Expr {
 		infixFunction, ok := p.InfixFunctions[p.CurrentToken().Type]
 
 		if !ok {
-			log.Fatalf("Could not parse infix token: %s, value: `%s` at line: %d, column: %d",
-				p.CurrentToken().Type, p.CurrentToken().Value, p.CurrentToken().Row+1,
-				p.CurrentToken().Col+1,
+			p.Errorf(p.CurrentToken(), "", "Could not parse infix token: %s, value: `%s`",
+				p.CurrentToken().Type, p.CurrentToken().Value,
 			)
 		}
 
@@ -187,7 +225,7 @@ func (p *Parser) ParseNodeDigit() ast.Expr {
 	val, e := strconv.ParseInt(p.CurrentToken().Value, 10, 64)
 
 	if e != nil {
-		log.Fatalf("Parser::Errors:%s\n", e)
+		p.ErrorCode(p.CurrentToken(), "", diagnostic.CODE_INVALID_LITERAL, "Invalid number `%s`", p.CurrentToken().Value)
 	}
 
 	return &ast.NodeInteger{
@@ -200,7 +238,7 @@ func (p *Parser) ParseNodeDouble() ast.Expr {
 	val, e := strconv.ParseFloat(p.CurrentToken().Value, 64)
 
 	if e != nil {
-		log.Fatalf("Parser::Errors:%s\n", e)
+		p.ErrorCode(p.CurrentToken(), "", diagnostic.CODE_INVALID_LITERAL, "Invalid number `%s`", p.CurrentToken().Value)
 	}
 
 	return &ast.NodeFloat{
@@ -209,16 +247,24 @@ func (p *Parser) ParseNodeDouble() ast.Expr {
 	}
 }
 
-func (p *Parser) ParseNodeNil() ast.Expr {
-	return &ast.NodeInteger{
+func (p *Parser) ParseNodeNull() ast.Expr {
+	return &ast.NodeNull{
 		Token: p.CurrentToken(),
-		Value: 999,
 	}
 }
 
 func (p *Parser) ParseBinaryExpr(left ast.Expr) ast.Expr {
 	currentToken := p.CurrentToken()
 
+	// only a field name can follow `.` and `?.`
+	if currentToken.Type == token.DOT || currentToken.Type == token.QUESTIONDOT {
+		if p.PeekToken().Type != token.IDENTIFIER {
+			p.Errorf(p.PeekToken(), token.IDENTIFIER, "Expect a field name after `%s`, got: %s `%s`",
+				currentToken.Value, p.PeekToken().Type, p.PeekToken().Value,
+			)
+		}
+	}
+
 	right := p.ParseExpression(p.GetOperatorPrecedence(currentToken))
 
 	return &ast.NodeBinaryExpr{
@@ -229,18 +275,113 @@ func (p *Parser) ParseBinaryExpr(left ast.Expr) ast.Expr {
 	}
 }
 
+// ParseAssignmentExpr parse `target = value` and the compound assignments,
+// they are right associative so `a = b = 1` assign both
+func (p *Parser) ParseAssignmentExpr(left ast.Expr) ast.Expr {
+	return p.parseAssignment(left, p.GetOperatorPrecedence(p.CurrentToken())-1)
+}
+
+// parseAssignment parse the value assigned to left, precedence is the
+// one the value is parsed with
+func (p *Parser) parseAssignment(left ast.Expr, precedence int) ast.Expr {
+	currentToken := p.CurrentToken()
+
+	if !ast.IsAssignable(left) {
+		p.Errorf(currentToken, "", "Invalid assignment target, expected a variable, an index or a field")
+	}
+
+	p.checkMutable(left)
+
+	right := p.ParseExpression(precedence)
+
+	return &ast.NodeBinaryExpr{
+		Token:    currentToken,
+		Left:     left,
+		Right:    right,
+		Operator: currentToken.Value,
+	}
+}
+
+// ParsePowerExpr parse `**` which is right associative, `2 ** 3 ** 2`
+// is `2 ** (3 ** 2)`
+func (p *Parser) ParsePowerExpr(left ast.Expr) ast.Expr {
+	currentToken := p.CurrentToken()
+
+	right := p.ParseExpression(p.GetOperatorPrecedence(currentToken) - 1)
+
+	return &ast.NodeBinaryExpr{
+		Token:    currentToken,
+		Left:     left,
+		Right:    right,
+		Operator: currentToken.Value,
+	}
+}
+
+// ParseGroupedExpr parse `(expr)`, the parentheses only override precedence
+func (p *Parser) ParseGroupedExpr() ast.Expr {
+	expr := p.ParseExpression(token.Precedence.LOWEST)
+	p.ExpectToken(token.RPAREN)
+
+	return expr
+}
+
+// ParseLogicalExpr parse `&&` and `||`, the `and` and `or` keywords
+// are stored with the symbol as operator
+func (p *Parser) ParseLogicalExpr(left ast.Expr) ast.Expr {
+	currentToken := p.CurrentToken()
+
+	right := p.ParseExpression(p.GetOperatorPrecedence(currentToken))
+
+	return &ast.NodeBinaryExpr{
+		Token:    currentToken,
+		Left:     left,
+		Right:    right,
+		Operator: currentToken.Type.Str(),
+	}
+}
+
+// ParseRangeExpr parse `start..end`, `start..<end` and an optional
+// `step n` following it, `step` is only a keyword in this position
+func (p *Parser) ParseRangeExpr(left ast.Expr) ast.Expr {
+	currentToken := p.CurrentToken()
+
+	node := &ast.NodeRangeExpr{
+		Token:     currentToken,
+		Start:     left,
+		End:       p.ParseExpression(p.GetOperatorPrecedence(currentToken)),
+		Inclusive: currentToken.Type == token.DOTDOT,
+	}
+
+	if p.PeekToken().Type == token.IDENTIFIER && p.PeekToken().Value == "step" {
+		p.ConsumeToken() // consume `step`
+		node.Step = p.ParseExpression(p.GetOperatorPrecedence(currentToken))
+	}
+
+	return node
+}
+
 func (p *Parser) ParsePrefixExpr() ast.Expr {
 	currentToken := p.CurrentToken() // the prefix
 
 	right := p.ParseExpression(token.Precedence.PREFIX)
 
+	if currentToken.Type == token.PLUSPLUS || currentToken.Type == token.MINUSMINUS {
+		p.checkMutable(right)
+	}
+
 	if currentToken.Value == "-" {
 		currentToken.Type = token.NEGATE
 	}
 
+	operator := currentToken.Value
+
+	if currentToken.Type == token.BANG {
+		operator = currentToken.Type.Str() // `not` is an alias of `!`
+	}
+
 	return &ast.NodePrefixExpr{
 		Token:    currentToken,
-		Operator: currentToken.Value,
+		Operator: operator,
 		Right:    right,
 	}
 }
@@ -296,11 +437,12 @@ func (p *Parser) ParseConditionExpr(left ast.Expr) ast.Expr {
 
 func (p *Parser) ParseConstDecl() ast.Stmt {
 	currentToken := p.CurrentToken()
-	identifier := p.ParseExpression(token.Precedence.ASSIGNMENT)
+	identifier := p.parseIdentifierExpr(token.Precedence.ASSIGNMENT)
 
 	p.ExpectToken(token.EQUAL) // consume `=`
 
 	value := p.ParseExpression(token.Precedence.LOWEST)
+	p.declare(identifier.Name, true)
 
 	return &ast.NodeConstStmt{
 		Token:      currentToken,
@@ -324,7 +466,11 @@ func (p *Parser) ParseImportDecl() ast.Expr {
 }
 
 func (p *Parser) ParseNodeString() ast.Expr {
-	v, _ := strconv.Unquote(p.CurrentToken().Value)
+	v, e := strconv.Unquote(p.CurrentToken().Value)
+
+	if e != nil {
+		p.ErrorCode(p.CurrentToken(), "", diagnostic.CODE_INVALID_LITERAL, "Invalid string literal %s", p.CurrentToken().Value)
+	}
 
 	return &ast.NodeString{
 		Token: p.CurrentToken(),
@@ -335,24 +481,22 @@ func (p *Parser) ParseNodeString() ast.Expr {
 func (p *Parser) ParseNodeStruct() ast.Stmt {
 	currentToken := p.CurrentToken()
 
-	identifier := p.ParseExpression(token.Precedence.EXPR)
+	identifier := p.parseIdentifierExpr(token.Precedence.EXPR)
 
 	p.skipEOL()
 
 	p.ExpectToken(token.LBRACE) // consume `{`
 
-	structProperties := make([]ast.Expr, 0)
+	fields := make([]*ast.NodeStructField, 0)
 
-	for p.PeekToken().Type != token.RBRACE {
+	for p.PeekToken().Type != token.RBRACE && p.PeekToken().Type != token.EOF {
 		p.skipEOL()
 
 		if p.PeekToken().Type == token.RBRACE {
 			break
 		}
 
-		identifier := p.ParseExpression(token.Precedence.LOWEST)
-
-		structProperties = append(structProperties, identifier)
+		fields = append(fields, p.parseStructField())
 
 		if p.PeekToken().Type == token.COMMA {
 			p.ConsumeToken() // consume `,`
@@ -360,18 +504,121 @@ func (p *Parser) ParseNodeStruct() ast.Stmt {
 	}
 
 	p.ExpectToken(token.RBRACE) // consume `}`
+	p.declare(identifier.Name, false)
 
 	return &ast.NodeStructStmt{
 		Token:      currentToken,
 		Identifier: identifier,
-		Properties: structProperties,
+		Fields:     fields,
 	}
 }
 
+// parseStructField parse a field of a struct declaration, `name`, `name =
+// default` or `name: required`. A field naming another struct embed it
+func (p *Parser) parseStructField() *ast.NodeStructField {
+	name := p.ExpectToken(token.IDENTIFIER)
+	field := &ast.NodeStructField{Token: name, Name: name.Value}
+
+	switch p.PeekToken().Type {
+	case token.EQUAL:
+		p.ExpectToken(token.EQUAL)
+		field.Default = p.ParseExpression(token.Precedence.LOWEST)
+
+	case token.COLON:
+		p.ExpectToken(token.COLON)
+		required := p.ExpectToken(token.IDENTIFIER)
+
+		if required.Value != "required" {
+			p.Errorf(required, "", "Expect `required` after the field %s, got: %s", name.Value, required.Value)
+		}
+
+		field.Required = true
+	}
+
+	return field
+}
+
+// ParseNodeEnum parse `enum Name { A, B(x, y) }`, a variant can carry
+// values, their names are given in parentheses
+func (p *Parser) ParseNodeEnum() ast.Stmt {
+	currentToken := p.CurrentToken()
+
+	identifier := p.parseIdentifierExpr(token.Precedence.EXPR)
+
+	p.skipEOL()
+
+	p.ExpectToken(token.LBRACE) // consume `{`
+
+	variants := make([]*ast.NodeEnumVariant, 0)
+	seen := make(map[string]bool)
+
+	for p.PeekToken().Type != token.RBRACE && p.PeekToken().Type != token.EOF {
+		p.skipEOL()
+
+		if p.PeekToken().Type == token.RBRACE {
+			break
+		}
+
+		name := p.parseIdentifierExpr(token.Precedence.EXPR)
+
+		if seen[name.Name] {
+			p.Errorf(name.Token, "", "Variant %s is already declared in %s", name.Name, identifier.Name)
+		}
+
+		seen[name.Name] = true
+		variant := &ast.NodeEnumVariant{Token: name.Token, Name: name.Name}
+
+		if p.PeekToken().Type == token.LPAREN {
+			p.ConsumeToken() // consume `(`
+			variant.Fields = p.parseVariantFields()
+			p.ExpectToken(token.RPAREN)
+		}
+
+		variants = append(variants, variant)
+
+		if p.PeekToken().Type == token.COMMA {
+			p.ConsumeToken() // consume `,`
+		}
+	}
+
+	p.ExpectToken(token.RBRACE) // consume `}`
+	p.declare(identifier.Name, false)
+
+	return &ast.NodeEnumStmt{
+		Token:      currentToken,
+		Identifier: identifier,
+		Variants:   variants,
+	}
+}
+
+// parseVariantFields parse the names in `Circle(radius, center)`, up to
+// the closing `)`
+func (p *Parser) parseVariantFields() []*ast.NodeIdentifier {
+	fields := make([]*ast.NodeIdentifier, 0)
+
+	for p.PeekToken().Type != token.RPAREN {
+		if p.PeekToken().Type != token.IDENTIFIER {
+			p.Errorf(p.PeekToken(), token.IDENTIFIER, "Expect a variant field name, got: %s `%s`",
+				p.PeekToken().Type, p.PeekToken().Value,
+			)
+		}
+
+		tok := p.ConsumeToken()
+		fields = append(fields, &ast.NodeIdentifier{Token: tok, Name: tok.Value})
+
+		if p.PeekToken().Type != token.COMMA {
+			break
+		}
+
+		p.ConsumeToken() // consume `,`
+	}
+
+	return fields
+}
+
 func (p *Parser) ParseNodeFunction() ast.Stmt {
 	currentToken := p.CurrentToken()
-	_identifier := p.ParseExpression(token.Precedence.EXPR)
-	var identifier ast.Expr = _identifier.(*ast.NodeIdentifier)
+	var identifier ast.Expr = p.parseIdentifierExpr(token.Precedence.EXPR)
 
 	if p.PeekToken().Type == token.DOT {
 		p.ExpectToken(token.DOT)        // consume `.`
@@ -385,11 +632,15 @@ func (p *Parser) ParseNodeFunction() ast.Stmt {
 		}
 	}
 
+	if ident, ok := identifier.(*ast.NodeIdentifier); ok {
+		p.declare(ident.Name, false)
+	}
+
 	p.ExpectToken(token.LPAREN)
-	arguements := p.ParseNodeFunctionArguement()
+	arguements := p.parseParameters()
 	p.ExpectToken(token.RPAREN)
 
-	body := p.parseBlockStmt()
+	body := p.parseFunctionBody(arguements)
 
 	return &ast.NodeFunctionStmt{
 		Token:      currentToken,
@@ -399,6 +650,23 @@ func (p *Parser) ParseNodeFunction() ast.Stmt {
 	}
 }
 
+// ParseFunctionExpr parse an anonymous function `fn(a, b) { ... }`
+func (p *Parser) ParseFunctionExpr() ast.Expr {
+	currentToken := p.CurrentToken()
+
+	p.ExpectToken(token.LPAREN)
+	arguements := p.parseParameters()
+	p.ExpectToken(token.RPAREN)
+
+	body := p.parseFunctionBody(arguements)
+
+	return &ast.NodeFunctionExpr{
+		Token:      currentToken,
+		Arguements: arguements,
+		Body:       body,
+	}
+}
+
 func (p *Parser) ParseNodeFunctionArguement() []ast.Expr {
 	arguements := make([]ast.Expr, 0)
 
@@ -414,6 +682,40 @@ func (p *Parser) ParseNodeFunctionArguement() []ast.Expr {
 	return arguements
 }
 
+// parseParameters parse the arguements of a function declaration, an
+// arguement can name the interface its value must implement, `item: Printable`
+func (p *Parser) parseParameters() []ast.Expr {
+	arguements := make([]ast.Expr, 0)
+
+	for p.PeekToken().Type != token.RPAREN {
+		arguement := p.ParseExpression(token.Precedence.LOWEST)
+
+		if p.PeekToken().Type == token.COLON {
+			identifier, ok := arguement.(*ast.NodeIdentifier)
+
+			if !ok {
+				p.Errorf(p.PeekToken(), "", "Only a named arguement can have an interface, got: %s", arguement)
+			}
+
+			tok := p.ExpectToken(token.COLON)
+
+			arguement = &ast.NodeTypedArguement{
+				Token:     tok,
+				Arguement: identifier,
+				Interface: p.parseIdentifierExpr(token.Precedence.EXPR),
+			}
+		}
+
+		arguements = append(arguements, arguement)
+
+		if p.PeekToken().Type == token.COMMA {
+			p.ExpectToken(token.COMMA) // consume `,`
+		}
+	}
+
+	return arguements
+}
+
 func (p *Parser) ParseFunctionCall(left ast.Expr) ast.Expr {
 	currentToken := p.CurrentToken()
 	functionArgs := p.ParseNodeFunctionArguement()
@@ -428,9 +730,10 @@ func (p *Parser) ParseFunctionCall(left ast.Expr) ast.Expr {
 
 func (p *Parser) ParseLetDecl() ast.Stmt {
 	currentToken := p.CurrentToken()
-	ident := p.ParseExpression(token.Precedence.ASSIGNMENT)
+	ident := p.parseIdentifierExpr(token.Precedence.ASSIGNMENT)
 	p.ExpectToken(token.EQUAL)
 	value := p.ParseExpression(token.Precedence.LOWEST)
+	p.declare(ident.Name, false)
 
 	return &ast.NodeLetStmt{
 		Token:      currentToken,
@@ -460,39 +763,79 @@ func (p *Parser) ParseArrayDecl() ast.Expr {
 	}
 }
 
+// ParseIndexExpr parse `xs[index]` and the slices `xs[start:end]`, either
+// bound of a slice can be left out
 func (p *Parser) ParseIndexExpr(left ast.Expr) ast.Expr {
 	currentToken := p.CurrentToken()
-	index := p.ParseExpression(token.Precedence.LOWEST)
+
+	var index ast.Expr
+
+	if p.PeekToken().Type != token.COLON {
+		index = p.ParseExpression(token.Precedence.LOWEST)
+	}
+
+	if p.PeekToken().Type != token.COLON {
+		p.ExpectToken(token.RBRACKET)
+
+		return &ast.NodeIndexExpr{
+			Token:      currentToken,
+			Identifier: left,
+			Index:      index,
+		}
+	}
+
+	p.ExpectToken(token.COLON)
+
+	var end ast.Expr
+
+	if p.PeekToken().Type != token.RBRACKET {
+		end = p.ParseExpression(token.Precedence.LOWEST)
+	}
+
 	p.ExpectToken(token.RBRACKET)
 
-	return &ast.NodeIndexExpr{
+	return &ast.NodeSliceExpr{
 		Token:      currentToken,
 		Identifier: left,
-		Index:      index,
+		Start:      index,
+		End:        end,
 	}
 }
 
+// ParseMapExpr parse `{key: value, ...}`, a bare identifier key is the
+// string of its name as in `{name: "kat"}`, any other key is an expression,
+// wrap an identifier in parentheses to use its value, `{(key): value}`
 func (p *Parser) ParseMapExpr() ast.Expr {
-	currentToken := p.CurrentToken()
-	values := make(map[ast.Expr]ast.Expr, 0)
+	node := &ast.NodeMapExpr{Token: p.CurrentToken()}
+
+	p.skipEOL()
 
 	for p.PeekToken().Type != token.RBRACE {
-		ident := p.ParseExpression(token.Precedence.LOWEST)
+		var key ast.Expr
+
+		if p.PeekAhead(1).Type == token.IDENTIFIER && p.PeekAhead(2).Type == token.COLON {
+			tok := p.ConsumeToken()
+			key = &ast.NodeString{Token: tok, Value: tok.Value}
+		} else {
+			key = p.ParseExpression(token.Precedence.LOWEST)
+		}
+
 		p.ExpectToken(token.COLON)
-		value := p.ParseExpression(token.Precedence.LOWEST)
-		values[ident] = value
+		p.skipEOL()
+
+		node.Keys = append(node.Keys, key)
+		node.Values = append(node.Values, p.ParseExpression(token.Precedence.LOWEST))
 
 		if p.PeekToken().Type == token.COMMA {
 			p.ExpectToken(token.COMMA)
 		}
+
+		p.skipEOL()
 	}
 
 	p.ExpectToken(token.RBRACE)
 
-	return &ast.NodeMapExpr{
-		Token: currentToken,
-		Map:   values,
-	}
+	return node
 }
 
 func (p *Parser) ParseStructExpr(left ast.Expr) ast.Expr {
@@ -537,6 +880,31 @@ func (p *Parser) ParseIfStmt() ast.Stmt {
 	return nodeIf
 }
 
+// ParseIfExpr parse `if cond { a } else { b }` where a value is expected,
+// as in `let x = if cond { a } else { b }`
+func (p *Parser) ParseIfExpr() ast.Expr {
+	currentToken := p.CurrentToken()
+
+	return &ast.NodeIfExpr{
+		Token:       currentToken,
+		Conditional: p.ParseIfStmt().(*ast.NodeConditionalStmt),
+	}
+}
+
+// parseIdentifierExpr parse an expression that must be a plain identifier
+func (p *Parser) parseIdentifierExpr(precedence int) *ast.NodeIdentifier {
+	expr := p.ParseExpression(precedence)
+	identifier, ok := expr.(*ast.NodeIdentifier)
+
+	if !ok {
+		p.Errorf(p.CurrentToken(), token.IDENTIFIER, "Expect an identifier, got: %s `%s`",
+			p.CurrentToken().Type, p.CurrentToken().Value,
+		)
+	}
+
+	return identifier
+}
+
 func (p *Parser) skipEOL() {
 	for p.PeekToken().Type == token.EOL {
 		p.ConsumeToken() // consume EOL
@@ -547,10 +915,16 @@ func (p *Parser) parseBlockStmt() ast.Stmt {
 	p.ExpectToken(token.LBRACE)
 	p.skipEOL()
 
+	p.pushScope()
+	defer p.popScope()
+
 	body := &ast.NodeBlockStmt{}
 
-	for p.PeekToken().Type != token.RBRACE {
-		body.Body = append(body.Body, p.ParseStatement())
+	for p.PeekToken().Type != token.RBRACE && p.PeekToken().Type != token.EOF {
+		if stmt := p.ParseStatementRecover(); stmt != nil {
+			body.Body = append(body.Body, stmt)
+		}
+
 		p.skipEOL()
 	}
 
@@ -559,15 +933,161 @@ func (p *Parser) parseBlockStmt() ast.Stmt {
 	return body
 }
 
+// parseFunctionBody parse the block of a function, loops outside the
+// function can't be the target of a break or continue inside it
+func (p *Parser) parseFunctionBody(arguements []ast.Expr) ast.Stmt {
+	loops := p.Loops
+	p.Loops = nil
+
+	p.pushScope()
+	p.declareArguements(arguements)
+
+	defer func() {
+		p.Loops = loops
+		p.popScope()
+	}()
+
+	return p.parseBlockStmt()
+}
+
 func (p *Parser) parseForStmt() ast.Stmt {
-	if p.PeekToken().Type == token.LET {
-		return p.parseClassicForStmt()
+	label := p.Label
+	p.Label = ""
+
+	p.Loops = append(p.Loops, label)
+
+	// The scope of the variables declared by the loop header
+	p.pushScope()
+
+	defer func() {
+		p.Loops = p.Loops[:len(p.Loops)-1]
+		p.popScope()
+	}()
+
+	var stmt ast.Stmt
+
+	switch {
+	case p.PeekToken().Type == token.LET:
+		stmt = p.parseClassicForStmt()
+
+	case p.isForInStmt():
+		stmt = p.parseForInStmt()
+
+	default:
+		stmt = p.parseModernForStmt()
+	}
+
+	switch node := stmt.(type) {
+	case *ast.NodeClassicForStmt:
+		node.Label = label
+
+	case *ast.NodeForInStmt:
+		node.Label = label
+
+	case *ast.NodeModernForStmt:
+		node.Label = label
+	}
+
+	return stmt
+}
+
+// isForInStmt report whether the loop is `for x in ...` or `for k, v in ...`
+func (p *Parser) isForInStmt() bool {
+	if p.PeekToken().Type != token.IDENTIFIER {
+		return false
+	}
+
+	if p.PeekAhead(2).Type == token.IN {
+		return true
 	}
 
-	return p.parseModernForStmt()
+	return p.PeekAhead(2).Type == token.COMMA &&
+		p.PeekAhead(3).Type == token.IDENTIFIER &&
+		p.PeekAhead(4).Type == token.IN
+}
+
+func (p *Parser) parseForInStmt() ast.Stmt {
+	node := &ast.NodeForInStmt{Token: p.CurrentToken()}
+
+	ident := p.ExpectToken(token.IDENTIFIER)
+	node.Value = &ast.NodeIdentifier{Token: ident, Name: ident.Value}
+	p.declare(ident.Value, false)
+
+	if p.PeekToken().Type == token.COMMA {
+		p.ExpectToken(token.COMMA)
+		ident = p.ExpectToken(token.IDENTIFIER)
+		p.declare(ident.Value, false)
+
+		node.Key = node.Value
+		node.Value = &ast.NodeIdentifier{Token: ident, Name: ident.Value}
+	}
+
+	p.ExpectToken(token.IN)
+
+	node.Iterable = p.ParseExpression(token.Precedence.LOWEST + 1)
+	node.Body = p.parseBlockStmt()
+
+	return node
+}
+
+// parseLabeledStmt parse `label: for ...`
+func (p *Parser) parseLabeledStmt() ast.Stmt {
+	label := p.ExpectToken(token.IDENTIFIER)
+	p.ExpectToken(token.COLON)
+	p.ExpectToken(token.FOR)
+
+	if util.InArray[string](p.Loops, label.Value) {
+		p.Errorf(label, "", "Label %s is already used by an enclosing loop", label.Value)
+	}
+
+	p.Label = label.Value
+
+	return p.parseForStmt()
+}
+
+func (p *Parser) parseBreakStmt() ast.Stmt {
+	currentToken := p.CurrentToken()
+
+	return &ast.NodeBreakStmt{
+		Token: currentToken,
+		Label: p.parseLoopLabel(),
+	}
+}
+
+func (p *Parser) parseContinueStmt() ast.Stmt {
+	currentToken := p.CurrentToken()
+
+	return &ast.NodeContinueStmt{
+		Token: currentToken,
+		Label: p.parseLoopLabel(),
+	}
+}
+
+// parseLoopLabel parse the optional label following `break` or `continue`
+// and make sure the statement is inside the loop it refer to
+func (p *Parser) parseLoopLabel() string {
+	keyword := p.CurrentToken()
+
+	if len(p.Loops) == 0 {
+		p.Errorf(keyword, "", "`%s` outside of a loop", keyword.Value)
+	}
+
+	if p.PeekToken().Type != token.IDENTIFIER {
+		return ""
+	}
+
+	label := p.ExpectToken(token.IDENTIFIER)
+
+	if !util.InArray[string](p.Loops, label.Value) {
+		p.Errorf(label, "", "Undefined loop label %s", label.Value)
+	}
+
+	return label.Value
 }
 
 func (p *Parser) parsePostfixExpr(left ast.Expr) ast.Expr {
+	p.checkMutable(left)
+
 	return &ast.NodePostfixExpr{
 		Token:    p.CurrentToken(),
 		Left:     left,
@@ -576,6 +1096,16 @@ func (p *Parser) parsePostfixExpr(left ast.Expr) ast.Expr {
 }
 
 func (p *Parser) ParseStatement() ast.Stmt {
+	// `fn(...)` is an anonymous function rather than a declaration
+	if p.PeekToken().Type == token.FUNCTION && p.PeekAhead(2).Type == token.LPAREN {
+		return p.ParseExpressionStatement()
+	}
+
+	// `label: for ...`
+	if p.PeekToken().Type == token.IDENTIFIER && p.PeekAhead(2).Type == token.COLON && p.PeekAhead(3).Type == token.FOR {
+		return p.parseLabeledStmt()
+	}
+
 	if p.StatementFunctions[p.PeekToken().Type] != nil {
 		p.ConsumeToken()
 		return p.StatementFunctions[p.CurrentToken().Type]()
@@ -610,6 +1140,13 @@ func (p *Parser) parseClassicForStmt() ast.Stmt {
 
 	postExpr := p.ParseExpression(token.Precedence.LOWEST + 1)
 
+	// An assignment, like `i += 2`, bind looser than what the header
+	// allow, its value is parsed the same way so `{` start the body
+	if token.IsAssignment(p.PeekToken().Type) {
+		p.ConsumeToken()
+		postExpr = p.parseAssignment(postExpr, token.Precedence.ASSIGNMENT)
+	}
+
 	body := p.parseBlockStmt()
 
 	return &ast.NodeClassicForStmt{
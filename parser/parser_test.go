@@ -0,0 +1,170 @@
+package parser
+
+import (
+	"kat/ast"
+	"kat/lexer"
+	"kat/token"
+	"testing"
+)
+
+func parse(source string) (*ast.NodeProgram, []*ParseError) {
+	p := New(lexer.NewWithFile("test.kat", []byte(source)))
+	return p.ParseProgram()
+}
+
+func TestParseProgramReportEveryError(t *testing.T) {
+	source := "let a = 1\n" +
+		"let = 2\n" +
+		"let b = 3 +\n" +
+		"const c = 4\n" +
+		"let d = )\n" +
+		"let e = 5\n"
+
+	program, errs := parse(source)
+
+	expected := []struct {
+		row, col int
+		tokType  token.TokenType
+	}{
+		{row: 1, col: 4, tokType: token.EQUAL},
+		{row: 2, col: 11, tokType: token.EOL},
+		{row: 4, col: 8, tokType: token.RPAREN},
+	}
+
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d errors, got %d: %v", len(expected), len(errs), errs)
+	}
+
+	for i, want := range expected {
+		tok := errs[i].Token
+
+		if tok.Row != want.row || tok.Col != want.col || tok.Type != want.tokType {
+			t.Errorf("error %d: expected %s at %d:%d, got %s at %d:%d",
+				i, want.tokType, want.row, want.col, tok.Type, tok.Row, tok.Col)
+		}
+
+		if tok.File != "test.kat" {
+			t.Errorf("error %d: expected file test.kat, got %s", i, tok.File)
+		}
+	}
+
+	// The statements around the bad ones are still parsed
+	names := make([]string, 0)
+
+	for _, stmt := range program.Body {
+		switch stmt := stmt.(type) {
+		case *ast.NodeLetStmt:
+			names = append(names, stmt.Identifier.(*ast.NodeIdentifier).Name)
+		case *ast.NodeConstStmt:
+			names = append(names, stmt.Identifier.(*ast.NodeIdentifier).Name)
+		}
+	}
+
+	want := []string{"a", "c", "e"}
+
+	if len(names) != len(want) {
+		t.Fatalf("expected statements %v, got %v", want, names)
+	}
+
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("expected statements %v, got %v", want, names)
+			break
+		}
+	}
+}
+
+func TestParseProgramRecoverInsideBlock(t *testing.T) {
+	source := "fn f() {\n" +
+		"    let = 1\n" +
+		"    return 2\n" +
+		"}\n" +
+		"let x = )\n" +
+		"f()\n"
+
+	program, errs := parse(source)
+
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %d: %v", len(errs), errs)
+	}
+
+	if errs[0].Token.Row != 1 || errs[1].Token.Row != 4 {
+		t.Errorf("expected errors on lines 2 and 5, got %d and %d", errs[0].Token.Row+1, errs[1].Token.Row+1)
+	}
+
+	if len(program.Body) != 2 {
+		t.Fatalf("expected the function and the call to be parsed, got %d statements", len(program.Body))
+	}
+
+	if _, ok := program.Body[0].(*ast.NodeFunctionStmt); !ok {
+		t.Errorf("expected a function declaration, got %T", program.Body[0])
+	}
+}
+
+func TestParseProgramWithoutError(t *testing.T) {
+	program, errs := parse("let a = 1\nconst b = a + 2\n")
+
+	if len(errs) != 0 {
+		t.Fatalf("expected no error, got %v", errs)
+	}
+
+	if len(program.Body) != 2 {
+		t.Errorf("expected 2 statements, got %d", len(program.Body))
+	}
+}
+
+func TestParseFieldAccessRequireIdentifier(t *testing.T) {
+	tests := []struct {
+		source   string
+		row, col int
+	}{
+		{source: "u.1\n", row: 0, col: 2},
+		{source: "u?.(1)\n", row: 0, col: 3},
+		{source: "let a = 1\na.\"x\" += 2\n", row: 1, col: 2},
+	}
+
+	for _, tt := range tests {
+		_, errs := parse(tt.source)
+
+		if len(errs) != 1 {
+			t.Errorf("%q: expected 1 error, got %d: %v", tt.source, len(errs), errs)
+			continue
+		}
+
+		tok := errs[0].Token
+
+		if tok.Row != tt.row || tok.Col != tt.col || errs[0].Expected != token.IDENTIFIER {
+			t.Errorf("%q: expected an identifier at %d:%d, got %s at %d:%d",
+				tt.source, tt.row, tt.col, tok.Type, tok.Row, tok.Col)
+		}
+	}
+
+	if _, errs := parse("let u = null\nu?.a.b\n"); len(errs) != 0 {
+		t.Errorf("expected no error, got %v", errs)
+	}
+}
+
+func TestParseEnumVariantFields(t *testing.T) {
+	program, errs := parse("enum Shape { Circle(radius), Rect(w, h), Empty }\n")
+
+	if len(errs) != 0 {
+		t.Fatalf("expected no error, got %v", errs)
+	}
+
+	variants := program.Body[0].(*ast.NodeEnumStmt).Variants
+
+	if len(variants[0].Fields) != 1 || len(variants[1].Fields) != 2 || len(variants[2].Fields) != 0 {
+		t.Errorf("expected 1, 2 and 0 fields, got %d, %d and %d",
+			len(variants[0].Fields), len(variants[1].Fields), len(variants[2].Fields))
+	}
+
+	_, errs = parse("enum E { A(1) }\n")
+
+	if len(errs) == 0 {
+		t.Fatalf("expected an error for a variant field that is not a name")
+	}
+
+	if tok := errs[0].Token; tok.Row != 0 || tok.Col != 11 || errs[0].Expected != token.IDENTIFIER {
+		t.Errorf("expected an identifier at 0:11, got %s at %d:%d", tok.Type, tok.Row, tok.Col)
+	}
+}
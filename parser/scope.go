@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"kat/ast"
+	"kat/diagnostic"
+)
+
+// scope hold the names declared in a block while parsing, mapped to
+// whether they are const, so assigning to a const can be reported before
+// the program run. Names the parser can't see, like the ones declared by
+// an earlier repl input, are still checked by the evaluator
+type scope map[string]bool
+
+func (p *Parser) pushScope() {
+	p.Scopes = append(p.Scopes, scope{})
+}
+
+func (p *Parser) popScope() {
+	p.Scopes = p.Scopes[:len(p.Scopes)-1]
+}
+
+func (p *Parser) declare(name string, isConst bool) {
+	p.Scopes[len(p.Scopes)-1][name] = isConst
+}
+
+// declareArguements declare the arguements of a function, `self` included
+func (p *Parser) declareArguements(arguements []ast.Expr) {
+	for _, arg := range arguements {
+		switch arg := arg.(type) {
+		case *ast.NodeIdentifier:
+			p.declare(arg.Name, false)
+
+		case *ast.NodeSelf:
+			p.declare(arg.Name, false)
+
+		case *ast.NodeTypedArguement:
+			p.declare(arg.Arguement.Name, false)
+		}
+	}
+}
+
+// isConst report whether the nearest declaration of name is a const
+func (p *Parser) isConst(name string) bool {
+	for i := len(p.Scopes) - 1; i >= 0; i-- {
+		if isConst, ok := p.Scopes[i][name]; ok {
+			return isConst
+		}
+	}
+
+	return false
+}
+
+// checkMutable report an error when target is a const variable
+func (p *Parser) checkMutable(target ast.Expr) {
+	ident, ok := target.(*ast.NodeIdentifier)
+
+	if ok && p.isConst(ident.Name) {
+		p.ErrorCode(ident.Token, "", diagnostic.CODE_ASSIGN_CONST, "Cannot assign to constant %s", ident.Name)
+	}
+}
@@ -0,0 +1,312 @@
+package repl
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"kat/ast"
+	"kat/diagnostic"
+	"kat/environment"
+	"kat/evaluator"
+	"kat/lexer"
+	"kat/parser"
+	"kat/token"
+	"kat/value"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	PROMPT       = ">> "
+	CONTINUATION = ".. "
+	HISTORY_FILE = ".kat_history"
+	REPL_FILE    = "<repl:%d>" // the name of the nth input in diagnostics
+)
+
+const help = `Meta commands:
+  :help           show this message
+  :ast <code>     dump the syntax tree of <code>
+  :tokens <code>  dump the tokens of <code>
+  :env            list the bindings of the session
+  :load <file>    evaluate a file into the session
+  :history        show the input history
+  :reset          start over with an empty session
+  :quit           leave the repl (or Ctrl-D)
+`
+
+type Repl struct {
+	In          *bufio.Scanner
+	Out         io.Writer
+	Env         *environment.Environment
+	History     []string
+	HistoryFile string
+	Sources     map[string][]byte // the evaluated inputs by file name, to render diagnostics
+	Inputs      int               // the number of inputs named so far
+}
+
+func New(in io.Reader, out io.Writer) *Repl {
+	r := &Repl{
+		In:      bufio.NewScanner(in),
+		Out:     out,
+		Env:     environment.New(),
+		History: make([]string, 0),
+		Sources: make(map[string][]byte),
+	}
+
+	if home, err := os.UserHomeDir(); err == nil {
+		r.HistoryFile = filepath.Join(home, HISTORY_FILE)
+		r.loadHistory()
+	}
+
+	return r
+}
+
+func Start(in io.Reader, out io.Writer) {
+	New(in, out).Run()
+}
+
+func (r *Repl) Run() {
+	fmt.Fprintln(r.Out, "Welcome To Kat Repl, type :help for help")
+
+	for {
+		input, ok := r.readInput()
+
+		if !ok {
+			fmt.Fprintln(r.Out)
+			return
+		}
+
+		if strings.TrimSpace(input) == "" {
+			continue
+		}
+
+		r.addHistory(input)
+
+		if strings.HasPrefix(strings.TrimSpace(input), ":") {
+			if quit := r.MetaCommand(strings.TrimSpace(input)); quit {
+				return
+			}
+
+			continue
+		}
+
+		r.Eval(r.inputName(), input)
+	}
+}
+
+// inputName give each input its own name, so a diagnostic pointing at an
+// earlier input, like an error inside a function it declared, show the
+// line of that input
+func (r *Repl) inputName() string {
+	r.Inputs++
+	return fmt.Sprintf(REPL_FILE, r.Inputs)
+}
+
+// readInput keep reading lines until the brackets are balanced, so
+// function, struct and map definitions can span multiple lines
+func (r *Repl) readInput() (string, bool) {
+	lines := make([]string, 0)
+	prompt := PROMPT
+
+	for {
+		fmt.Fprint(r.Out, prompt)
+
+		if !r.In.Scan() {
+			return strings.Join(lines, "\n"), len(lines) > 0
+		}
+
+		lines = append(lines, r.In.Text())
+		input := strings.Join(lines, "\n")
+
+		if strings.HasPrefix(strings.TrimSpace(input), ":") || !IsIncomplete(input) {
+			return input, true
+		}
+
+		prompt = CONTINUATION
+	}
+}
+
+// IsIncomplete report whether the input still has unclosed `{`, `(` or `[`
+func IsIncomplete(input string) bool {
+	l := lexer.New([]byte(input))
+	depth := 0
+
+	for t := l.NextToken(); t.Type != token.EOF; t = l.NextToken() {
+		switch t.Type {
+		case token.LBRACE, token.LPAREN, token.LBRACKET:
+			depth++
+
+		case token.RBRACE, token.RPAREN, token.RBRACKET:
+			depth--
+		}
+	}
+
+	return depth > 0
+}
+
+// Eval evaluate the input in the session environment and print the value
+// of every expression statement, errors don't discard the session
+func (r *Repl) Eval(file string, input string) {
+	defer func() {
+		if err := recover(); err != nil {
+			fmt.Fprintf(r.Out, "internal error: %v\n", err)
+		}
+	}()
+
+	source := []byte(input)
+	r.Sources[file] = source
+	program, errs := parser.New(lexer.NewWithFile(file, source)).ParseProgram()
+
+	if len(errs) > 0 {
+		for _, err := range errs {
+			r.report(err.Diagnostic())
+		}
+
+		return
+	}
+
+	e := evaluator.New(program)
+
+	for _, stmt := range program.Body {
+		res := e.Eval(stmt, r.Env)
+
+		if ret, ok := res.(*value.Return); ok {
+			res = ret.Value
+		}
+
+		if err, ok := res.(*value.Error); ok {
+			r.report(err.Diagnostic())
+			return
+		}
+
+		if _, ok := stmt.(*ast.NodeExprStmt); ok && res != value.NULL {
+			fmt.Fprintln(r.Out, res)
+		}
+	}
+}
+
+// MetaCommand run a `:command`, it return true when the repl should quit
+func (r *Repl) MetaCommand(input string) bool {
+	command, arg, _ := strings.Cut(input, " ")
+	arg = strings.TrimSpace(arg)
+
+	switch command {
+	case ":quit", ":q", ":exit":
+		return true
+
+	case ":help":
+		fmt.Fprint(r.Out, help)
+
+	case ":ast":
+		file := r.inputName()
+		r.Sources[file] = []byte(arg)
+		program, errs := parser.New(lexer.NewWithFile(file, []byte(arg))).ParseProgram()
+
+		for _, err := range errs {
+			r.report(err.Diagnostic())
+		}
+
+		fmt.Fprintln(r.Out, program.String())
+
+	case ":tokens":
+		l := lexer.New([]byte(arg))
+
+		for t := l.NextToken(); t.Type != token.EOF; t = l.NextToken() {
+			fmt.Fprintln(r.Out, t.Dump())
+		}
+
+	case ":env":
+		r.printEnv()
+
+	case ":load":
+		source, err := os.ReadFile(arg)
+
+		if err != nil {
+			fmt.Fprintln(r.Out, err)
+			break
+		}
+
+		r.Eval(arg, string(source))
+
+	case ":history":
+		for i, h := range r.History {
+			fmt.Fprintf(r.Out, "%4d  %s\n", i+1, h)
+		}
+
+	case ":reset":
+		r.Env = environment.New()
+
+	default:
+		fmt.Fprintf(r.Out, "Unknown command %s, type :help for help\n", command)
+	}
+
+	return false
+}
+
+// report render the diagnostic against the input it refer to
+func (r *Repl) report(d *diagnostic.Diagnostic) {
+	fmt.Fprint(r.Out, d.Render(r.Sources[d.File], diagnostic.UseColor(r.Out)))
+}
+
+func (r *Repl) printEnv() {
+	keys := make([]string, 0, len(r.Env.Envs))
+
+	for k := range r.Env.Envs {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v := r.Env.Envs[k]
+		fmt.Fprintf(r.Out, "%s: %s = %s\n", k, v.Type(), v)
+	}
+}
+
+func (r *Repl) loadHistory() {
+	content, err := os.ReadFile(r.HistoryFile)
+
+	if err != nil {
+		return
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		if line == "" {
+			continue
+		}
+
+		input, err := strconv.Unquote(line)
+
+		if err != nil {
+			// a line written before the inputs were quoted
+			input = strings.ReplaceAll(line, "\\n", "\n")
+		}
+
+		r.History = append(r.History, input)
+	}
+}
+
+// addHistory remember the input and persist it, quoted so a multi-line
+// input is stored as a single line
+func (r *Repl) addHistory(input string) {
+	r.History = append(r.History, input)
+
+	if r.HistoryFile == "" {
+		return
+	}
+
+	f, err := os.OpenFile(r.HistoryFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	_, _ = f.WriteString(strconv.Quote(input) + "\n")
+}
@@ -0,0 +1,160 @@
+package stdlib
+
+import (
+	"fmt"
+	"kat/value"
+	"slices"
+	"strings"
+)
+
+var ArrayMethods = map[string]Method{}
+
+func init() {
+	ArrayMethods["len"] = arrayLen
+	ArrayMethods["push"] = arrayPush
+	ArrayMethods["pop"] = arrayPop
+	ArrayMethods["insert"] = arrayInsert
+	ArrayMethods["contains"] = arrayContains
+	ArrayMethods["index_of"] = arrayIndexOf
+	ArrayMethods["join"] = arrayJoin
+	ArrayMethods["reverse"] = arrayReverse
+}
+
+func arrayLen(self value.Value, args ...value.Value) value.Value {
+	if err := checkArgs("len", args, 0); err != nil {
+		return err
+	}
+
+	return &value.Int{Value: int64(len(self.(*value.Array).Value))}
+}
+
+// arrayPush append the arguments at the end of the array
+func arrayPush(self value.Value, args ...value.Value) value.Value {
+	if err := checkMutable(self); err != nil {
+		return err
+	}
+
+	arr := self.(*value.Array)
+	arr.Value = append(arr.Value, args...)
+
+	return value.NULL
+}
+
+// arrayPop remove the last element and return it
+func arrayPop(self value.Value, args ...value.Value) value.Value {
+	if err := checkArgs("pop", args, 0); err != nil {
+		return err
+	}
+
+	if err := checkMutable(self); err != nil {
+		return err
+	}
+
+	arr := self.(*value.Array)
+
+	if len(arr.Value) == 0 {
+		return &value.Error{Value: "Can't pop from an empty array"}
+	}
+
+	last := arr.Value[len(arr.Value)-1]
+	arr.Value = arr.Value[:len(arr.Value)-1]
+
+	return last
+}
+
+// arrayInsert insert a value before the index, an index equal to the
+// length append it
+func arrayInsert(self value.Value, args ...value.Value) value.Value {
+	if err := checkArgs("insert", args, 2); err != nil {
+		return err
+	}
+
+	if err := checkMutable(self); err != nil {
+		return err
+	}
+
+	arr := self.(*value.Array)
+	index, ok := args[0].(*value.Int)
+
+	if !ok {
+		msg := fmt.Sprintf("Bad argument 1 for insert, expected an int, got %s", args[0].Type())
+		return &value.Error{Value: msg}
+	}
+
+	if index.Value < 0 || index.Value > int64(len(arr.Value)) {
+		msg := fmt.Sprintf("Insert index %d out of range, length is %d", index.Value, len(arr.Value))
+		return &value.Error{Value: msg}
+	}
+
+	arr.Value = slices.Insert(arr.Value, int(index.Value), args[1])
+
+	return value.NULL
+}
+
+func arrayContains(self value.Value, args ...value.Value) value.Value {
+	if err := checkArgs("contains", args, 1); err != nil {
+		return err
+	}
+
+	return nativeBool(indexOf(self.(*value.Array), args[0]) >= 0)
+}
+
+// arrayIndexOf return the index of the first element equal to the
+// argument, -1 when there is none
+func arrayIndexOf(self value.Value, args ...value.Value) value.Value {
+	if err := checkArgs("index_of", args, 1); err != nil {
+		return err
+	}
+
+	return &value.Int{Value: int64(indexOf(self.(*value.Array), args[0]))}
+}
+
+// arrayJoin join the elements printed as by println with the separator
+func arrayJoin(self value.Value, args ...value.Value) value.Value {
+	if err := checkArgs("join", args, 1); err != nil {
+		return err
+	}
+
+	sep, err := stringArg("join", args, 0)
+
+	if err != nil {
+		return err
+	}
+
+	elements := make([]string, len(self.(*value.Array).Value))
+
+	for i, elem := range self.(*value.Array).Value {
+		elements[i] = elem.String()
+	}
+
+	return &value.String{Value: strings.Join(elements, sep)}
+}
+
+// arrayReverse reverse the array in place
+func arrayReverse(self value.Value, args ...value.Value) value.Value {
+	if err := checkArgs("reverse", args, 0); err != nil {
+		return err
+	}
+
+	if err := checkMutable(self); err != nil {
+		return err
+	}
+
+	slices.Reverse(self.(*value.Array).Value)
+
+	return value.NULL
+}
+
+func indexOf(arr *value.Array, val value.Value) int {
+	return slices.IndexFunc(arr.Value, func(elem value.Value) bool {
+		return value.Equal(elem, val)
+	})
+}
+
+func nativeBool(b bool) value.Value {
+	if b {
+		return value.TRUE
+	}
+
+	return value.FALSE
+}
@@ -0,0 +1,77 @@
+package stdlib
+
+import (
+	"kat/value"
+)
+
+var MapMethods = map[string]Method{}
+
+func init() {
+	MapMethods["len"] = mapLen
+	MapMethods["keys"] = mapKeys
+	MapMethods["values"] = mapValues
+	MapMethods["has"] = mapHas
+	MapMethods["delete"] = mapDelete
+}
+
+func mapLen(self value.Value, args ...value.Value) value.Value {
+	if err := checkArgs("len", args, 0); err != nil {
+		return err
+	}
+
+	return &value.Int{Value: int64(self.(*value.Map[value.Value]).Len())}
+}
+
+// mapKeys return the keys in insertion order
+func mapKeys(self value.Value, args ...value.Value) value.Value {
+	if err := checkArgs("keys", args, 0); err != nil {
+		return err
+	}
+
+	entries := self.(*value.Map[value.Value]).Entries
+	keys := make([]value.Value, len(entries))
+
+	for i, entry := range entries {
+		keys[i] = entry.Key
+	}
+
+	return &value.Array{Value: keys}
+}
+
+// mapValues return the values in insertion order
+func mapValues(self value.Value, args ...value.Value) value.Value {
+	if err := checkArgs("values", args, 0); err != nil {
+		return err
+	}
+
+	entries := self.(*value.Map[value.Value]).Entries
+	values := make([]value.Value, len(entries))
+
+	for i, entry := range entries {
+		values[i] = entry.Value
+	}
+
+	return &value.Array{Value: values}
+}
+
+func mapHas(self value.Value, args ...value.Value) value.Value {
+	if err := checkArgs("has", args, 1); err != nil {
+		return err
+	}
+
+	_, ok := self.(*value.Map[value.Value]).Get(args[0])
+	return nativeBool(ok)
+}
+
+// mapDelete remove the key and report whether it was there
+func mapDelete(self value.Value, args ...value.Value) value.Value {
+	if err := checkArgs("delete", args, 1); err != nil {
+		return err
+	}
+
+	if err := checkMutable(self); err != nil {
+		return err
+	}
+
+	return nativeBool(self.(*value.Map[value.Value]).Delete(args[0]))
+}
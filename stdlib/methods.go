@@ -0,0 +1,62 @@
+package stdlib
+
+import (
+	"fmt"
+	"kat/value"
+)
+
+// Method is a native method of a built-in type, self is the value it is
+// called on
+type Method func(self value.Value, args ...value.Value) value.Value
+
+// MethodsOf return the native methods of the type of val, nil when the
+// type has none
+func MethodsOf(val value.Value) map[string]Method {
+	switch val.(type) {
+	case *value.Array:
+		return ArrayMethods
+
+	case *value.String:
+		return StringMethods
+
+	case *value.Map[value.Value]:
+		return MapMethods
+
+	case *value.Int, *value.Float:
+		return NumberMethods
+	}
+
+	return nil
+}
+
+// checkArgs report an error when the method name isn't given count arguments
+func checkArgs(name string, args []value.Value, count int) *value.Error {
+	if len(args) != count {
+		msg := fmt.Sprintf("Bad arguments for %s, expected %d, got %d", name, count, len(args))
+		return &value.Error{Value: msg}
+	}
+
+	return nil
+}
+
+// stringArg return the argument i of the method name, which must be a string
+func stringArg(name string, args []value.Value, i int) (string, *value.Error) {
+	s, ok := args[i].(*value.String)
+
+	if !ok {
+		msg := fmt.Sprintf("Bad argument %d for %s, expected a string, got %s", i+1, name, args[i].Type())
+		return "", &value.Error{Value: msg}
+	}
+
+	return s.Value, nil
+}
+
+// checkMutable report an error when self can't be mutated
+func checkMutable(self value.Value) *value.Error {
+	if value.IsFrozen(self) {
+		msg := fmt.Sprintf("Cannot mutate a frozen %s", self.Type())
+		return &value.Error{Value: msg}
+	}
+
+	return nil
+}
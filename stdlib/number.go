@@ -0,0 +1,75 @@
+package stdlib
+
+import (
+	"fmt"
+	"kat/value"
+	"math"
+	"strconv"
+)
+
+var NumberMethods = map[string]Method{}
+
+func init() {
+	NumberMethods["abs"] = numberAbs
+	NumberMethods["round"] = numberRound
+	NumberMethods["to_string"] = numberToString
+}
+
+func numberAbs(self value.Value, args ...value.Value) value.Value {
+	if err := checkArgs("abs", args, 0); err != nil {
+		return err
+	}
+
+	switch n := self.(type) {
+	case *value.Int:
+		// -MinInt64 wraps back to MinInt64, a negative abs
+		if n.Value == math.MinInt64 {
+			msg := fmt.Sprintf("Integer overflow: abs(%d)", n.Value)
+			return &value.Error{Value: msg}
+		}
+
+		if n.Value < 0 {
+			return &value.Int{Value: -n.Value}
+		}
+
+	case *value.Float:
+		return &value.Float{Value: math.Abs(n.Value)}
+	}
+
+	return self
+}
+
+// numberRound round a float to the nearest int, half away from zero, NaN,
+// the infinities and the floats out of the int range have no such int
+func numberRound(self value.Value, args ...value.Value) value.Value {
+	if err := checkArgs("round", args, 0); err != nil {
+		return err
+	}
+
+	if n, ok := self.(*value.Float); ok {
+		rounded, ok := value.ExactInt(math.Round(n.Value))
+
+		if !ok {
+			msg := fmt.Sprintf("Can't round %v to an int", n.Value)
+			return &value.Error{Value: msg}
+		}
+
+		return &value.Int{Value: rounded}
+	}
+
+	return self
+}
+
+// numberToString format the number, a float with the fewest digits that
+// read back to it rather than the two decimals println show
+func numberToString(self value.Value, args ...value.Value) value.Value {
+	if err := checkArgs("to_string", args, 0); err != nil {
+		return err
+	}
+
+	if n, ok := self.(*value.Float); ok {
+		return &value.String{Value: strconv.FormatFloat(n.Value, 'g', -1, 64)}
+	}
+
+	return &value.String{Value: self.String()}
+}
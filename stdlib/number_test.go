@@ -0,0 +1,57 @@
+package stdlib
+
+import (
+	"kat/value"
+	"math"
+	"testing"
+)
+
+func TestNumberToString(t *testing.T) {
+	tests := []struct {
+		number   value.Value
+		expected string
+	}{
+		{number: &value.Float{Value: 3.14159}, expected: "3.14159"},
+		{number: &value.Float{Value: 2}, expected: "2"},
+		{number: &value.Float{Value: 0.1}, expected: "0.1"},
+		{number: &value.Int{Value: -7}, expected: "-7"},
+	}
+
+	for _, tt := range tests {
+		result := numberToString(tt.number)
+
+		if s, ok := result.(*value.String); !ok || s.Value != tt.expected {
+			t.Errorf("%s.to_string(): expected %s, got %s", tt.number, tt.expected, result)
+		}
+	}
+}
+
+func TestNumberRound(t *testing.T) {
+	for number, expected := range map[float64]int64{2.5: 3, -2.5: -3, 2.4: 2} {
+		result := numberRound(&value.Float{Value: number})
+
+		if i, ok := result.(*value.Int); !ok || i.Value != expected {
+			t.Errorf("%v.round(): expected %d, got %s", number, expected, result)
+		}
+	}
+
+	for _, number := range []float64{math.NaN(), math.Inf(1), math.Inf(-1), 1e300} {
+		if _, ok := numberRound(&value.Float{Value: number}).(*value.Error); !ok {
+			t.Errorf("%v.round(): expected an error", number)
+		}
+	}
+}
+
+func TestNumberAbs(t *testing.T) {
+	for number, expected := range map[int64]int64{-3: 3, 3: 3, 0: 0, math.MinInt64 + 1: math.MaxInt64} {
+		result := numberAbs(&value.Int{Value: number})
+
+		if i, ok := result.(*value.Int); !ok || i.Value != expected {
+			t.Errorf("%d.abs(): expected %d, got %s", number, expected, result)
+		}
+	}
+
+	if _, ok := numberAbs(&value.Int{Value: math.MinInt64}).(*value.Error); !ok {
+		t.Errorf("%d.abs(): expected an overflow error", int64(math.MinInt64))
+	}
+}
@@ -0,0 +1,44 @@
+package stdlib
+
+import (
+	"kat/value"
+	"os"
+)
+
+var OsFuncs = map[string]value.Value{}
+
+// scriptArgs holds the script path followed by the arguments given
+// on the command line, see SetArgs
+var scriptArgs = make([]string, 0)
+
+func init() {
+	OsFuncs["args"] = &value.WrapperFunction{Name: "args", Fn: Args}
+	OsFuncs["exit"] = &value.WrapperFunction{Name: "exit", Fn: Exit}
+}
+
+func SetArgs(args []string) {
+	scriptArgs = args
+}
+
+func Args(varargs ...value.Value) value.Value {
+	args := make([]value.Value, 0, len(scriptArgs))
+
+	for _, arg := range scriptArgs {
+		args = append(args, &value.String{Value: arg})
+	}
+
+	return &value.Array{Value: args}
+}
+
+func Exit(varargs ...value.Value) value.Value {
+	code := 0
+
+	if len(varargs) > 0 {
+		if c, ok := varargs[0].(*value.Int); ok {
+			code = int(c.Value)
+		}
+	}
+
+	os.Exit(code)
+	return value.NULL
+}
@@ -0,0 +1,124 @@
+package stdlib
+
+import (
+	"kat/value"
+	"strings"
+	"unicode/utf8"
+)
+
+var StringMethods = map[string]Method{}
+
+func init() {
+	StringMethods["len"] = stringLen
+	StringMethods["split"] = stringSplit
+	StringMethods["trim"] = stringTrim
+	StringMethods["upper"] = stringUpper
+	StringMethods["lower"] = stringLower
+	StringMethods["replace"] = stringReplace
+	StringMethods["starts_with"] = stringStartsWith
+	StringMethods["ends_with"] = stringEndsWith
+}
+
+// stringLen count the runes, like indexing does
+func stringLen(self value.Value, args ...value.Value) value.Value {
+	if err := checkArgs("len", args, 0); err != nil {
+		return err
+	}
+
+	return &value.Int{Value: int64(utf8.RuneCountInString(self.(*value.String).Value))}
+}
+
+func stringSplit(self value.Value, args ...value.Value) value.Value {
+	if err := checkArgs("split", args, 1); err != nil {
+		return err
+	}
+
+	sep, err := stringArg("split", args, 0)
+
+	if err != nil {
+		return err
+	}
+
+	parts := strings.Split(self.(*value.String).Value, sep)
+	elements := make([]value.Value, len(parts))
+
+	for i, part := range parts {
+		elements[i] = &value.String{Value: part}
+	}
+
+	return &value.Array{Value: elements}
+}
+
+// stringTrim remove the leading and trailing white spaces
+func stringTrim(self value.Value, args ...value.Value) value.Value {
+	if err := checkArgs("trim", args, 0); err != nil {
+		return err
+	}
+
+	return &value.String{Value: strings.TrimSpace(self.(*value.String).Value)}
+}
+
+func stringUpper(self value.Value, args ...value.Value) value.Value {
+	if err := checkArgs("upper", args, 0); err != nil {
+		return err
+	}
+
+	return &value.String{Value: strings.ToUpper(self.(*value.String).Value)}
+}
+
+func stringLower(self value.Value, args ...value.Value) value.Value {
+	if err := checkArgs("lower", args, 0); err != nil {
+		return err
+	}
+
+	return &value.String{Value: strings.ToLower(self.(*value.String).Value)}
+}
+
+// stringReplace replace every occurrence of the first argument by the second
+func stringReplace(self value.Value, args ...value.Value) value.Value {
+	if err := checkArgs("replace", args, 2); err != nil {
+		return err
+	}
+
+	old, err := stringArg("replace", args, 0)
+
+	if err != nil {
+		return err
+	}
+
+	replacement, err := stringArg("replace", args, 1)
+
+	if err != nil {
+		return err
+	}
+
+	return &value.String{Value: strings.ReplaceAll(self.(*value.String).Value, old, replacement)}
+}
+
+func stringStartsWith(self value.Value, args ...value.Value) value.Value {
+	if err := checkArgs("starts_with", args, 1); err != nil {
+		return err
+	}
+
+	prefix, err := stringArg("starts_with", args, 0)
+
+	if err != nil {
+		return err
+	}
+
+	return nativeBool(strings.HasPrefix(self.(*value.String).Value, prefix))
+}
+
+func stringEndsWith(self value.Value, args ...value.Value) value.Value {
+	if err := checkArgs("ends_with", args, 1); err != nil {
+		return err
+	}
+
+	suffix, err := stringArg("ends_with", args, 0)
+
+	if err != nil {
+		return err
+	}
+
+	return nativeBool(strings.HasSuffix(self.(*value.String).Value, suffix))
+}
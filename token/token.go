@@ -8,29 +8,46 @@ var Precedence = struct {
 	LOWEST      int
 	ASSIGNMENT  int
 	CONDITIONAL int
+	COALESCE    int
+	LOGICAL_OR  int
+	LOGICAL_AND int
 	COMPARISON  int
+	RANGE       int
+	BITOR       int
+	BITXOR      int
+	BITAND      int
+	SHIFT       int
 	SUM         int
 	PRODUCT     int
-	EXPONENT    int
 	PREFIX      int
+	EXPONENT    int
 	POSTFIX     int
 	EXPR        int
 }{
 	LOWEST:      0,
 	ASSIGNMENT:  1,
 	CONDITIONAL: 2,
-	COMPARISON:  3,
-	SUM:         4,
-	PRODUCT:     5,
-	EXPONENT:    6,
-	PREFIX:      7,
-	POSTFIX:     8,
-	EXPR:        9,
+	COALESCE:    3,
+	LOGICAL_OR:  4,
+	LOGICAL_AND: 5,
+	COMPARISON:  6,
+	RANGE:       7,
+	BITOR:       8,
+	BITXOR:      9,
+	BITAND:      10,
+	SHIFT:       11,
+	SUM:         12,
+	PRODUCT:     13,
+	PREFIX:      14,
+	EXPONENT:    15, // above PREFIX so -2 ** 2 is -(2 ** 2)
+	POSTFIX:     16,
+	EXPR:        17,
 }
 
 type TokenType string
 
 type Token struct {
+	File  string
 	Row   int
 	Col   int
 	Value string
@@ -44,55 +61,92 @@ func (t Token) String() string {
 	)
 }
 
+// Dump is String with the 1-based line and column of the diagnostics, it
+// is how `kat tokens` and the repl `:tokens` print a token
+func (t Token) Dump() string {
+	t.Row++
+	t.Col++
+	return t.String()
+}
+
 func (tt TokenType) Str() string {
 	return TokenString[tt]
 }
 
 var TokenString = map[TokenType]string{
-	PLUS:         "+",
-	MINUS:        "-",
-	NEGATE:       "-",
-	BANG:         "!",
-	QUESTION:     "?",
-	MULTIPLY:     "*",
-	DIVIDE:       "/",
-	MODULO:       "%",
-	EQUAL:        "=",
-	LESS:         "<",
-	GREATER:      ">",
-	LBRACKET:     "[",
-	RBRACKET:     "]",
-	LBRACE:       "{",
-	RBRACE:       "}",
-	COLON:        ":",
-	LPAREN:       "(",
-	RPAREN:       ")",
-	COMMA:        ",",
-	SEMICOLON:    ";",
-	DOT:          ".",
-	PLUSPLUS:     "++",
-	MINUSMINUS:   "--",
-	EQUALEQUAL:   "==",
-	GREATEREQUAL: ">=",
-	LESSEQUAL:    "<=",
-	STRING:       "string",
-	INTEGER:      "integer",
-	DOUBLE:       "double",
-	TRUE:         "true",
-	FALSE:        "false",
-	LET:          "let",
-	CONST:        "const",
-	IF:           "if",
-	ELSE:         "else",
-	FOR:          "for",
-	SELF:         "self",
-	IMPORT:       "import",
-	STRUCT:       "struct",
-	FUNCTION:     "function",
-	IDENTIFIER:   "identifier",
-	EOL:          "eol",
-	EOF:          "eof",
-	INVALID:      "invalid",
+	PLUS:          "+",
+	MINUS:         "-",
+	NEGATE:        "-",
+	BANG:          "!",
+	QUESTION:      "?",
+	MULTIPLY:      "*",
+	POWER:         "**",
+	BITAND:        "&",
+	BITOR:         "|",
+	BITXOR:        "^",
+	TILDE:         "~",
+	SHIFTLEFT:     "<<",
+	SHIFTRIGHT:    ">>",
+	DIVIDE:        "/",
+	MODULO:        "%",
+	EQUAL:         "=",
+	LESS:          "<",
+	GREATER:       ">",
+	LBRACKET:      "[",
+	RBRACKET:      "]",
+	LBRACE:        "{",
+	RBRACE:        "}",
+	COLON:         ":",
+	LPAREN:        "(",
+	RPAREN:        ")",
+	COMMA:         ",",
+	SEMICOLON:     ";",
+	DOT:           ".",
+	QUESTIONDOT:   "?.",
+	COALESCE:      "??",
+	DOTDOT:        "..",
+	DOTDOTLESS:    "..<",
+	PLUSPLUS:      "++",
+	MINUSMINUS:    "--",
+	EQUALEQUAL:    "==",
+	FATARROW:      "=>",
+	GREATEREQUAL:  ">=",
+	LESSEQUAL:     "<=",
+	PLUSEQUAL:     "+=",
+	MINUSEQUAL:    "-=",
+	MULTIPLYEQUAL: "*=",
+	DIVIDEEQUAL:   "/=",
+	MODULOEQUAL:   "%=",
+	AND:           "&&",
+	OR:            "||",
+	STRING:        "string",
+	INTEGER:       "integer",
+	DOUBLE:        "double",
+	TRUE:          "true",
+	FALSE:         "false",
+	NULL:          "null",
+	LET:           "let",
+	CONST:         "const",
+	IF:            "if",
+	ELSE:          "else",
+	FOR:           "for",
+	IN:            "in",
+	SELF:          "self",
+	IMPORT:        "import",
+	STRUCT:        "struct",
+	ENUM:          "enum",
+	INTERFACE:     "interface",
+	IMPL:          "impl",
+	IS:            "is",
+	FUNCTION:      "function",
+	RETURN:        "return",
+	BREAK:         "break",
+	CONTINUE:      "continue",
+	MATCH:         "match",
+	IDENTIFIER:    "identifier",
+	EOL:           "eol",
+	EOF:           "eof",
+	INVALID:       "invalid",
 }
 
 const (
@@ -118,15 +172,36 @@ const (
 	COMMA     = "COMMA"     // ,
 	SEMICOLON = "SEMICOLON" // ;
 	DOT       = "DOT"       // .
+	BITAND    = "BITAND"    // &
+	BITOR     = "BITOR"     // |
+	BITXOR    = "BITXOR"    // ^
+	TILDE     = "TILDE"     // ~
 
 	// Double character
-	PLUSPLUS     = "PLUSPLUS"     // ++
-	MINUSMINUS   = "MINUSMINUS"   // --
-	EQUALEQUAL   = "EQUALEQUAL"   // ==
-	NOTEQUAL     = "NOTEQUAL"     // ==
-	GREATEREQUAL = "GREATEREQUAL" // >=
-	LESSEQUAL    = "LESSEQUAL"    // <=
-	COMMENT      = "COMMENT"      // //
+	PLUSPLUS      = "PLUSPLUS"      // ++
+	MINUSMINUS    = "MINUSMINUS"    // --
+	EQUALEQUAL    = "EQUALEQUAL"    // ==
+	FATARROW      = "FATARROW"      // =>
+	NOTEQUAL      = "NOTEQUAL"      // ==
+	GREATEREQUAL  = "GREATEREQUAL"  // >=
+	LESSEQUAL     = "LESSEQUAL"     // <=
+	PLUSEQUAL     = "PLUSEQUAL"     // +=
+	MINUSEQUAL    = "MINUSEQUAL"    // -=
+	MULTIPLYEQUAL = "MULTIPLYEQUAL" // *=
+	DIVIDEEQUAL   = "DIVIDEEQUAL"   // /=
+	MODULOEQUAL   = "MODULOEQUAL"   // %=
+	AND           = "AND"           // && or and
+	POWER         = "POWER"         // **
+	SHIFTLEFT     = "SHIFTLEFT"     // <<
+	SHIFTRIGHT    = "SHIFTRIGHT"    // >>
+	OR            = "OR"            // || or or
+	COMMENT       = "COMMENT"       // //
+	DOTDOT        = "DOTDOT"        // ..
+	QUESTIONDOT   = "QUESTIONDOT"   // ?.
+	COALESCE      = "COALESCE"      // ??
+
+	// Triple character
+	DOTDOTLESS = "DOTDOTLESS" // ..<
 
 	// Literal
 	STRING  = "STRING"
@@ -136,16 +211,25 @@ const (
 	// Keyword
 	TRUE       = "TRUE"       // true
 	FALSE      = "FALSE"      // false
+	NULL       = "NULL"       // null
 	LET        = "LET"        // let
 	CONST      = "CONST"      // const
 	IF         = "IF"         // if
 	ELSE       = "ELSE"       // else
 	FOR        = "FOR"        // for
+	IN         = "IN"         // in
 	SELF       = "SELF"       // self
 	IMPORT     = "IMPORT"     // import
 	STRUCT     = "STRUCT"     // struct
+	ENUM       = "ENUM"       // enum
+	INTERFACE  = "INTERFACE"  // interface
+	IMPL       = "IMPL"       // impl
+	IS         = "IS"         // is
 	FUNCTION   = "FUNCTION"   // fn
 	RETURN     = "RETURN"     // return
+	BREAK      = "BREAK"      // break
+	CONTINUE   = "CONTINUE"   // continue
+	MATCH      = "MATCH"      // match
 	IDENTIFIER = "IDENTIFIER" // any
 
 	// Special
@@ -156,18 +240,30 @@ const (
 
 func Symbol(key string) TokenType {
 	keywords := map[string]TokenType{
-		"true":   TRUE,
-		"false":  FALSE,
-		"let":    LET,
-		"const":  CONST,
-		"if":     IF,
-		"else":   ELSE,
-		"for":    FOR,
-		"self":   SELF,
-		"import": IMPORT,
-		"struct": STRUCT,
-		"fn":     FUNCTION,
-		"return": RETURN,
+		"true":      TRUE,
+		"false":     FALSE,
+		"null":      NULL,
+		"let":       LET,
+		"const":     CONST,
+		"if":        IF,
+		"else":      ELSE,
+		"for":       FOR,
+		"in":        IN,
+		"and":       AND,
+		"or":        OR,
+		"not":       BANG,
+		"self":      SELF,
+		"import":    IMPORT,
+		"struct":    STRUCT,
+		"enum":      ENUM,
+		"interface": INTERFACE,
+		"impl":      IMPL,
+		"is":        IS,
+		"fn":        FUNCTION,
+		"return":    RETURN,
+		"break":     BREAK,
+		"continue":  CONTINUE,
+		"match":     MATCH,
 	}
 
 	keyword, ok := keywords[key]
@@ -185,12 +281,34 @@ func GetPrecedence(tok Token) int {
 		LBRACE: Precedence.ASSIGNMENT,
 		EQUAL:  Precedence.ASSIGNMENT,
 
+		PLUSEQUAL:     Precedence.ASSIGNMENT,
+		MINUSEQUAL:    Precedence.ASSIGNMENT,
+		MULTIPLYEQUAL: Precedence.ASSIGNMENT,
+		DIVIDEEQUAL:   Precedence.ASSIGNMENT,
+		MODULOEQUAL:   Precedence.ASSIGNMENT,
+
+		COALESCE: Precedence.COALESCE,
+
+		OR:  Precedence.LOGICAL_OR,
+		AND: Precedence.LOGICAL_AND,
+
 		LESS:         Precedence.COMPARISON,
 		GREATER:      Precedence.COMPARISON,
 		LESSEQUAL:    Precedence.COMPARISON,
 		GREATEREQUAL: Precedence.COMPARISON,
 		EQUALEQUAL:   Precedence.COMPARISON,
 		NOTEQUAL:     Precedence.COMPARISON,
+		IN:           Precedence.COMPARISON,
+		IS:           Precedence.COMPARISON,
+
+		DOTDOT:     Precedence.RANGE,
+		DOTDOTLESS: Precedence.RANGE,
+
+		BITOR:      Precedence.BITOR,
+		BITXOR:     Precedence.BITXOR,
+		BITAND:     Precedence.BITAND,
+		SHIFTLEFT:  Precedence.SHIFT,
+		SHIFTRIGHT: Precedence.SHIFT,
 
 		PLUS:  Precedence.SUM,
 		MINUS: Precedence.SUM,
@@ -199,15 +317,19 @@ func GetPrecedence(tok Token) int {
 		DIVIDE:   Precedence.PRODUCT,
 		MODULO:   Precedence.PRODUCT,
 
+		POWER: Precedence.EXPONENT,
+
 		NEGATE: Precedence.PREFIX,
 		BANG:   Precedence.PREFIX,
+		TILDE:  Precedence.PREFIX,
 
-		QUESTION:   Precedence.CONDITIONAL,
-		LPAREN:     Precedence.EXPR,
-		LBRACKET:   Precedence.EXPR,
-		DOT:        Precedence.EXPR,
-		MINUSMINUS: Precedence.PREFIX,
-		PLUSPLUS:   Precedence.PREFIX,
+		QUESTION:    Precedence.CONDITIONAL,
+		LPAREN:      Precedence.EXPR,
+		LBRACKET:    Precedence.EXPR,
+		DOT:         Precedence.EXPR,
+		QUESTIONDOT: Precedence.EXPR,
+		MINUSMINUS:  Precedence.PREFIX,
+		PLUSPLUS:    Precedence.PREFIX,
 	}
 
 	precedence, ok := precedences[tok.Type]
@@ -218,3 +340,13 @@ func GetPrecedence(tok Token) int {
 
 	return 0
 }
+
+// IsAssignment report whether the token is `=` or a compound assignment
+func IsAssignment(tt TokenType) bool {
+	switch tt {
+	case EQUAL, PLUSEQUAL, MINUSEQUAL, MULTIPLYEQUAL, DIVIDEEQUAL, MODULOEQUAL:
+		return true
+	}
+
+	return false
+}
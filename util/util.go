@@ -3,7 +3,6 @@ package util
 import (
 	"fmt"
 	"io"
-	"kat/value"
 	"log"
 	"os"
 )
@@ -12,21 +11,6 @@ func TypeOf(v any) string {
 	return fmt.Sprintf("%T", v)
 }
 
-func IsTruthy(v value.Value) bool {
-	switch v.(type) {
-	case *value.Int:
-		return v.(*value.Int).Value != 0
-
-	case *value.Float:
-		return v.(*value.Float).Value != 0
-
-	case *value.Bool:
-		return v.(*value.Bool).Value
-	}
-
-	return false
-}
-
 func InArray[T comparable](arr []T, key T) bool {
 	for _, v := range arr {
 		if v == key {
@@ -51,7 +35,3 @@ func ReadFile(fileName string) []byte {
 	}
 	return source
 }
-
-func Repl() {
-	fmt.Println("Welcome To Kat Repl")
-}
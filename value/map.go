@@ -0,0 +1,117 @@
+package value
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MapEntry is one key with its value in a Map
+type MapEntry[T any] struct {
+	Key   Value
+	Value T
+}
+
+// Map is a hash map keeping its entries in insertion order, any value
+// accepted by Hash can be a key and keys are compared with Equal, so
+// `m[1]` and `m[1.0]` are the same entry
+type Map[T any] struct {
+	Entries []*MapEntry[T]   // in insertion order
+	Frozen  bool             // set by Freeze, the entries can't be changed
+	index   map[uint64][]int // hash of a key to its positions in Entries
+}
+
+func NewMap[T any]() *Map[T] {
+	return &Map[T]{index: make(map[uint64][]int)}
+}
+
+// find return the position of key in Entries or -1
+func (m *Map[T]) find(key Value, hash uint64) int {
+	for _, i := range m.index[hash] {
+		if Equal(m.Entries[i].Key, key) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Get return the value stored under key, ok is false when there is none
+// or the key can't be hashed
+func (m *Map[T]) Get(key Value) (val T, ok bool) {
+	hash, err := Hash(key)
+
+	if err != nil {
+		return val, false
+	}
+
+	if i := m.find(key, hash); i >= 0 {
+		return m.Entries[i].Value, true
+	}
+
+	return val, false
+}
+
+// Set store val under key, a new key is added after the existing ones and
+// an existing key keep its position. It fail when the key can't be hashed
+func (m *Map[T]) Set(key Value, val T) error {
+	hash, err := Hash(key)
+
+	if err != nil {
+		return err
+	}
+
+	if i := m.find(key, hash); i >= 0 {
+		m.Entries[i].Value = val
+		return nil
+	}
+
+	m.index[hash] = append(m.index[hash], len(m.Entries))
+	m.Entries = append(m.Entries, &MapEntry[T]{Key: key, Value: val})
+
+	return nil
+}
+
+// Delete remove key from the map, ok is false when it was not there
+func (m *Map[T]) Delete(key Value) (ok bool) {
+	hash, err := Hash(key)
+
+	if err != nil {
+		return false
+	}
+
+	i := m.find(key, hash)
+
+	if i < 0 {
+		return false
+	}
+
+	m.Entries = append(m.Entries[:i], m.Entries[i+1:]...)
+
+	// The positions after i moved, rebuild the index
+	m.index = make(map[uint64][]int, len(m.Entries))
+
+	for pos, entry := range m.Entries {
+		h, _ := Hash(entry.Key)
+		m.index[h] = append(m.index[h], pos)
+	}
+
+	return true
+}
+
+func (m *Map[T]) Len() int {
+	return len(m.Entries)
+}
+
+func (m *Map[T]) String() string {
+	entries := make([]string, 0, len(m.Entries))
+
+	for _, entry := range m.Entries {
+		entries = append(entries, fmt.Sprintf("%s: %v", entry.Key, entry.Value))
+	}
+
+	return fmt.Sprintf("{%s}", strings.Join(entries, ", "))
+}
+
+func (m *Map[T]) Type() Type {
+	return TYPE_MAP
+}
@@ -0,0 +1,107 @@
+package value
+
+import (
+	"testing"
+)
+
+func keys(m *Map[Value]) []string {
+	keys := make([]string, len(m.Entries))
+
+	for i, entry := range m.Entries {
+		keys[i] = entry.Key.String()
+	}
+
+	return keys
+}
+
+func expectKeys(t *testing.T, m *Map[Value], expected ...string) {
+	t.Helper()
+
+	got := keys(m)
+
+	if len(got) != len(expected) {
+		t.Fatalf("expected keys %v, got %v", expected, got)
+	}
+
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Fatalf("expected keys %v, got %v", expected, got)
+		}
+	}
+}
+
+func TestMapSetGet(t *testing.T) {
+	m := NewMap[Value]()
+
+	for i, key := range []Value{&String{Value: "a"}, &Int{Value: 1}, TRUE} {
+		if err := m.Set(key, &Int{Value: int64(i)}); err != nil {
+			t.Fatalf("Set(%s): unexpected error %v", key, err)
+		}
+	}
+
+	if val, ok := m.Get(&String{Value: "a"}); !ok || !Equal(val, &Int{Value: 0}) {
+		t.Errorf("Get(a): expected 0, got %v %t", val, ok)
+	}
+
+	// 1.0 is the same key as 1
+	if val, ok := m.Get(&Float{Value: 1}); !ok || !Equal(val, &Int{Value: 1}) {
+		t.Errorf("Get(1.0): expected 1, got %v %t", val, ok)
+	}
+
+	if _, ok := m.Get(&String{Value: "missing"}); ok {
+		t.Errorf("Get(missing): expected no value")
+	}
+
+	// Overwriting keep the position
+	_ = m.Set(&Float{Value: 1}, &String{Value: "one"})
+	expectKeys(t, m, "a", "1", "true")
+
+	if m.Len() != 3 {
+		t.Errorf("expected 3 entries, got %d", m.Len())
+	}
+
+	if err := m.Set(&Array{}, NULL); err == nil {
+		t.Errorf("Set(array): expected an error")
+	}
+
+	if _, ok := m.Get(&Array{}); ok {
+		t.Errorf("Get(array): expected no value")
+	}
+}
+
+func TestMapDeleteAndOrder(t *testing.T) {
+	m := NewMap[Value]()
+
+	for _, key := range []string{"a", "b", "c", "d"} {
+		_ = m.Set(&String{Value: key}, &String{Value: key})
+	}
+
+	if !m.Delete(&String{Value: "b"}) {
+		t.Fatalf("Delete(b): expected true")
+	}
+
+	if m.Delete(&String{Value: "b"}) {
+		t.Errorf("Delete(b) twice: expected false")
+	}
+
+	expectKeys(t, m, "a", "c", "d")
+
+	// The remaining keys are still found after the index is rebuilt
+	for _, key := range []string{"a", "c", "d"} {
+		if val, ok := m.Get(&String{Value: key}); !ok || val.String() != key {
+			t.Errorf("Get(%s): expected %s, got %v %t", key, key, val, ok)
+		}
+	}
+
+	// A re-inserted key go to the end
+	_ = m.Set(&String{Value: "b"}, &String{Value: "b"})
+	expectKeys(t, m, "a", "c", "d", "b")
+
+	_ = m.Set(&String{Value: "e"}, &String{Value: "e"})
+	m.Delete(&String{Value: "a"})
+	expectKeys(t, m, "c", "d", "b", "e")
+
+	if m.String() != "{c: c, d: d, b: b, e: e}" {
+		t.Errorf("unexpected String(): %s", m.String())
+	}
+}
@@ -0,0 +1,296 @@
+package value
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+)
+
+// Equal report whether a and b are the same value, ints and floats are
+// compared exactly by value so `1 == 1.0`, strings, arrays, maps, structs,
+// ranges and enum values are compared by content, anything else is only
+// equal to itself
+func Equal(a Value, b Value) bool {
+	switch a := a.(type) {
+	case *Int:
+		switch b := b.(type) {
+		case *Int:
+			return a.Value == b.Value
+
+		case *Float:
+			i, ok := ExactInt(b.Value)
+			return ok && i == a.Value
+		}
+
+	case *Float:
+		switch b := b.(type) {
+		case *Int:
+			i, ok := ExactInt(a.Value)
+			return ok && i == b.Value
+
+		case *Float:
+			return a.Value == b.Value
+		}
+
+	case *String:
+		b, ok := b.(*String)
+		return ok && a.Value == b.Value
+
+	case *Bool:
+		b, ok := b.(*Bool)
+		return ok && a.Value == b.Value
+
+	case *Null:
+		_, ok := b.(*Null)
+		return ok
+
+	case *Range:
+		b, ok := b.(*Range)
+		return ok && *a == *b
+
+	case *Array:
+		b, ok := b.(*Array)
+
+		if !ok || len(a.Value) != len(b.Value) {
+			return false
+		}
+
+		for i := range a.Value {
+			if !Equal(a.Value[i], b.Value[i]) {
+				return false
+			}
+		}
+
+		return true
+
+	case *Map[Value]:
+		b, ok := b.(*Map[Value])
+
+		if !ok || a.Len() != b.Len() {
+			return false
+		}
+
+		for _, entry := range a.Entries {
+			bv, ok := b.Get(entry.Key)
+
+			if !ok || !Equal(entry.Value, bv) {
+				return false
+			}
+		}
+
+		return true
+
+	case *Struct[Value]:
+		b, ok := b.(*Struct[Value])
+		return ok && a.StructType == b.StructType && equalKeyVal(a.KeyVal, b.KeyVal)
+
+	case *EnumValue:
+		b, ok := b.(*EnumValue)
+
+		if !ok || a.Enum != b.Enum || a.Variant != b.Variant || len(a.Values) != len(b.Values) {
+			return false
+		}
+
+		for i := range a.Values {
+			if !Equal(a.Values[i], b.Values[i]) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return a == b
+}
+
+func equalKeyVal(a *KeyVal[Value], b *KeyVal[Value]) bool {
+	if len(a.Map) != len(b.Map) {
+		return false
+	}
+
+	for k, av := range a.Map {
+		bv, ok := b.Map[k]
+
+		if !ok || !Equal(av, bv) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsTruthy report whether v count as true in a condition, the falsy
+// values are false, null, 0, 0.0, NaN, "" and the empty array and map,
+// everything else is truthy
+func IsTruthy(v Value) bool {
+	switch v := v.(type) {
+	case *Bool:
+		return v.Value
+
+	case *Null:
+		return false
+
+	case *Int:
+		return v.Value != 0
+
+	case *Float:
+		return v.Value != 0 && !math.IsNaN(v.Value)
+
+	case *String:
+		return v.Value != ""
+
+	case *Array:
+		return len(v.Value) > 0
+
+	case *Map[Value]:
+		return v.Len() > 0
+	}
+
+	return true
+}
+
+// Hash return a hash of v consistent with Equal, so equal values hash the
+// same, a float holding an integral value hash like the int. Only
+// immutable values can be hashed, arrays, maps, structs and functions
+// return an error, so does an enum value carrying one of them
+func Hash(v Value) (uint64, error) {
+	h := fnv.New64a()
+	buf := make([]byte, 8)
+
+	// Prefix each kind with a tag so `1`, `"1"` and `true` don't collide
+	write := func(tag byte, bits uint64) {
+		binary.LittleEndian.PutUint64(buf, bits)
+		h.Write([]byte{tag})
+		h.Write(buf)
+	}
+
+	switch v := v.(type) {
+	case *Int:
+		write('i', uint64(v.Value))
+
+	case *Float:
+		if i, ok := ExactInt(v.Value); ok {
+			write('i', uint64(i))
+		} else {
+			write('f', math.Float64bits(v.Value))
+		}
+
+	case *String:
+		h.Write([]byte{'s'})
+		h.Write([]byte(v.Value))
+
+	case *Bool:
+		bits := uint64(0)
+
+		if v.Value {
+			bits = 1
+		}
+
+		write('b', bits)
+
+	case *Null:
+		h.Write([]byte{'n'})
+
+	case *Range:
+		write('r', uint64(v.Start))
+		write('r', uint64(v.End))
+		write('r', uint64(v.Step))
+
+		if v.Inclusive {
+			h.Write([]byte{'='})
+		}
+
+	case *EnumValue:
+		h.Write([]byte{'e'})
+		// Equal values share the declaration, its name is enough to hash
+		h.Write([]byte(v.Enum.Name + "." + v.Variant.Name))
+
+		for _, val := range v.Values {
+			hash, err := Hash(val)
+
+			if err != nil {
+				return 0, err
+			}
+
+			write('v', hash)
+		}
+
+	default:
+		return 0, fmt.Errorf("Unhashable type: %s", v.Type())
+	}
+
+	return h.Sum64(), nil
+}
+
+// ExactInt return the int equal to f, ok is false when f isn't integral or
+// is out of the int range. Comparing ints and floats through it rather than
+// converting the int to a float keep Equal exact above 2^53, where floats
+// can't represent every int, and so keep Hash consistent with it
+func ExactInt(f float64) (int64, bool) {
+	// -2^63 and 2^63 are exact floats, unlike math.MaxInt64
+	if f != math.Trunc(f) || f < math.MinInt64 || f >= -math.MinInt64 {
+		return 0, false
+	}
+
+	return int64(f), true
+}
+
+// Freeze make v and every array, map and struct reachable from it
+// immutable, see IsFrozen
+func Freeze(v Value) {
+	switch v := v.(type) {
+	case *Array:
+		if v.Frozen {
+			return
+		}
+
+		v.Frozen = true
+
+		for _, elem := range v.Value {
+			Freeze(elem)
+		}
+
+	case *Map[Value]:
+		if v.Frozen {
+			return
+		}
+
+		v.Frozen = true
+
+		for _, entry := range v.Entries {
+			Freeze(entry.Value)
+		}
+
+	case *Struct[Value]:
+		freezeKeyVal(v.KeyVal)
+	}
+}
+
+func freezeKeyVal(kv *KeyVal[Value]) {
+	if kv.Frozen {
+		return
+	}
+
+	kv.Frozen = true
+
+	for _, val := range kv.Map {
+		Freeze(val)
+	}
+}
+
+// IsFrozen report whether v is an array, map or struct that can't be mutated
+func IsFrozen(v Value) bool {
+	switch v := v.(type) {
+	case *Array:
+		return v.Frozen
+
+	case *Map[Value]:
+		return v.Frozen
+
+	case *Struct[Value]:
+		return v.Frozen
+	}
+
+	return false
+}
@@ -0,0 +1,92 @@
+package value
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEqualNumbers(t *testing.T) {
+	tests := []struct {
+		a, b     Value
+		expected bool
+	}{
+		{a: &Int{Value: 1}, b: &Int{Value: 1}, expected: true},
+		{a: &Int{Value: 1}, b: &Float{Value: 1}, expected: true},
+		{a: &Float{Value: 1}, b: &Int{Value: 1}, expected: true},
+		{a: &Int{Value: 1}, b: &Float{Value: 1.5}, expected: false},
+		{a: &Float{Value: math.NaN()}, b: &Float{Value: math.NaN()}, expected: false},
+		{a: &Int{Value: math.MaxInt64}, b: &Float{Value: math.MaxInt64}, expected: false},
+		{a: &Int{Value: 1 << 53}, b: &Float{Value: 1 << 53}, expected: true},
+		{a: &Int{Value: 1<<53 + 1}, b: &Float{Value: 1 << 53}, expected: false},
+		{a: &Int{Value: math.MinInt64}, b: &Float{Value: math.MinInt64}, expected: true},
+		{a: &Int{Value: 1}, b: &String{Value: "1"}, expected: false},
+		{a: &Int{Value: 1}, b: TRUE, expected: false},
+	}
+
+	for _, tt := range tests {
+		if got := Equal(tt.a, tt.b); got != tt.expected {
+			t.Errorf("Equal(%s %s, %s %s): expected %t, got %t", tt.a.Type(), tt.a, tt.b.Type(), tt.b, tt.expected, got)
+		}
+	}
+}
+
+func TestHashAgreeWithEqual(t *testing.T) {
+	values := []Value{
+		&Int{Value: 1},
+		&Float{Value: 1},
+		&Float{Value: 1.5},
+		&Int{Value: -3},
+		&Float{Value: -3},
+		&Int{Value: math.MinInt64},
+		&Float{Value: math.MinInt64},
+		&Int{Value: 1<<53 + 1},
+		&Float{Value: 1 << 53},
+		&Float{Value: math.Inf(1)},
+		&String{Value: "1"},
+		TRUE,
+		NULL,
+	}
+
+	for _, a := range values {
+		for _, b := range values {
+			if !Equal(a, b) {
+				continue
+			}
+
+			ha, errA := Hash(a)
+			hb, errB := Hash(b)
+
+			if errA != nil || errB != nil {
+				t.Fatalf("Hash(%s), Hash(%s): unexpected error %v %v", a, b, errA, errB)
+			}
+
+			if ha != hb {
+				t.Errorf("%s %s and %s %s are equal but hash differently", a.Type(), a, b.Type(), b)
+			}
+		}
+	}
+
+	// Different kinds holding the same text don't collide
+	hi, _ := Hash(&Int{Value: 1})
+	hs, _ := Hash(&String{Value: "1"})
+
+	if hi == hs {
+		t.Errorf("1 and \"1\" hash the same")
+	}
+}
+
+func TestHashUnhashable(t *testing.T) {
+	structType := &StructType{Name: "User", Fields: []*StructField{{Name: "name"}}}
+
+	values := []Value{
+		&Array{Value: []Value{&Int{Value: 1}}},
+		NewMap[Value](),
+		&Struct[Value]{StructType: structType, KeyVal: &KeyVal[Value]{Map: map[string]Value{"name": NULL}}},
+	}
+
+	for _, v := range values {
+		if _, err := Hash(v); err == nil {
+			t.Errorf("Hash(%s): expected an error", v.Type())
+		}
+	}
+}
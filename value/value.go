@@ -3,6 +3,8 @@ package value
 import (
 	"fmt"
 	"kat/ast"
+	"kat/diagnostic"
+	"kat/token"
 	"strings"
 )
 
@@ -15,13 +17,18 @@ const (
 	TYPE_STRING       Type = "string"
 	TYPE_ARRAY        Type = "array"
 	TYPE_MAP          Type = "map"
+	TYPE_RANGE        Type = "range"
 	TYPE_STRUCT       Type = "struct"
+	TYPE_ENUM         Type = "enum"
+	TYPE_INTERFACE    Type = "interface"
 	TYPE_FUNCTION     Type = "function"
 	TYPE_MODULE       Type = "module"
 	TYPE_NULL         Type = "null"
 	TYPE_SELF         Type = "self"
 	TYPE_KEYVAL       Type = "keyval"
 	TYPE_RETURN       Type = "return"
+	TYPE_BREAK        Type = "break"
+	TYPE_CONTINUE     Type = "continue"
 	TYPE_ERROR        Type = "error"
 	TYPE_STD_FUNCTION Type = "std_function"
 	TYPE_ENVIRONMENT  Type = "environment"
@@ -109,8 +116,10 @@ func (n *Null) Type() Type {
 }
 
 type Function struct {
-	Args []Value
-	Body ast.Stmt
+	Args     []Value
+	Body     ast.Stmt
+	Env      Value             // the environment the function was defined in
+	Requires map[string]string // argument name to the interface its value must implement
 }
 
 func (f *Function) String() string {
@@ -121,17 +130,71 @@ func (f *Function) Type() Type {
 	return TYPE_FUNCTION
 }
 
+// StructType is the type declared by `struct`, it hold the declared
+// fields and the functions declared on it
+type StructType struct {
+	Name    string
+	Fields  []*StructField
+	Methods map[string]*Function
+	Embeds  []*StructType // the embedded structs, searched in order for methods
+}
+
+func (st *StructType) String() string {
+	return st.Name
+}
+
+func (st *StructType) Type() Type {
+	return TYPE_STRUCT
+}
+
+// Field return the declared field name, nil when there is none
+func (st *StructType) Field(name string) *StructField {
+	for _, field := range st.Fields {
+		if field.Name == name {
+			return field
+		}
+	}
+
+	return nil
+}
+
+// Method return the function name declared on the struct or on one of
+// the structs it embed
+func (st *StructType) Method(name string) (*Function, bool) {
+	if fn, ok := st.Methods[name]; ok {
+		return fn, true
+	}
+
+	for _, embedded := range st.Embeds {
+		if fn, ok := embedded.Method(name); ok {
+			return fn, true
+		}
+	}
+
+	return nil, false
+}
+
+// StructField is a field declared by a struct, the fields of the embedded
+// structs included
+type StructField struct {
+	Name     string
+	Default  ast.Expr // nil when the field has no default
+	Env      Value    // the environment the default is evaluated in
+	Required bool
+}
+
+// Struct is a value of a struct type, it has a value for each field of
+// its type
 type Struct[T any] struct {
-	Name string
-	Prop []string
+	*StructType
 	*KeyVal[T]
 }
 
 func (s *Struct[T]) String() string {
 	valStruct := make([]string, 0)
 
-	for _, k := range s.Prop {
-		valStruct = append(valStruct, fmt.Sprintf("%s: %s", k, s.Map[k]))
+	for _, field := range s.Fields {
+		valStruct = append(valStruct, fmt.Sprintf("%s: %v", field.Name, s.Map[field.Name]))
 	}
 
 	return fmt.Sprintf("%s{%s}", s.Name, strings.Join(valStruct, ", "))
@@ -141,20 +204,91 @@ func (s *Struct[T]) Type() Type {
 	return TYPE_STRUCT
 }
 
-type Map[T any] struct {
-	*KeyVal[T]
+// Interface is a set of methods declared by `interface`, a struct
+// implement it when it has all of them
+type Interface struct {
+	Name    string
+	Methods []*InterfaceMethod
+}
+
+func (i *Interface) String() string {
+	return i.Name
+}
+
+func (i *Interface) Type() Type {
+	return TYPE_INTERFACE
+}
+
+// InterfaceMethod is a method required by an interface, Args are the
+// names of its arguements, `self` included
+type InterfaceMethod struct {
+	Name string
+	Args []string
+}
+
+func (m *InterfaceMethod) String() string {
+	return fmt.Sprintf("%s(%s)", m.Name, strings.Join(m.Args, ", "))
+}
+
+// Enum is the type declared by `enum`, with its variants in order
+type Enum struct {
+	Name     string
+	Variants []*EnumVariant
+}
+
+func (en *Enum) String() string {
+	return en.Name
+}
+
+func (en *Enum) Type() Type {
+	return TYPE_ENUM
+}
+
+// Variant return the variant called name or nil
+func (en *Enum) Variant(name string) *EnumVariant {
+	for _, variant := range en.Variants {
+		if variant.Name == name {
+			return variant
+		}
+	}
+
+	return nil
 }
 
-func (m *Map[T]) String() string {
-	return "valuemap"
+// EnumVariant is one variant of an enum, Fields name the values it carry
+type EnumVariant struct {
+	Name   string
+	Fields []string
 }
 
-func (m *Map[T]) Type() Type {
-	return TYPE_MAP
+// EnumValue is a variant of an enum with the values it carry
+type EnumValue struct {
+	Enum    *Enum // the declaration, values of a shadowed enum are distinct
+	Variant *EnumVariant
+	Values  []Value // one for each of Variant.Fields
+}
+
+func (ev *EnumValue) String() string {
+	if len(ev.Values) == 0 {
+		return fmt.Sprintf("%s.%s", ev.Enum.Name, ev.Variant.Name)
+	}
+
+	values := make([]string, len(ev.Values))
+
+	for i, val := range ev.Values {
+		values[i] = val.String()
+	}
+
+	return fmt.Sprintf("%s.%s(%s)", ev.Enum.Name, ev.Variant.Name, strings.Join(values, ", "))
+}
+
+func (ev *EnumValue) Type() Type {
+	return TYPE_ENUM
 }
 
 type KeyVal[T any] struct {
-	Map map[string]T
+	Map    map[string]T
+	Frozen bool // set by Freeze, the entries can't be changed
 }
 
 func (kv *KeyVal[T]) String() string {
@@ -177,6 +311,72 @@ func (r *Return) Type() Type {
 	return TYPE_RETURN
 }
 
+// Break and Continue travel up through the blocks until the loop
+// they target, the innermost one when Label is empty
+type Break struct {
+	Label string
+}
+
+func (b *Break) String() string {
+	return "break"
+}
+
+func (b *Break) Type() Type {
+	return TYPE_BREAK
+}
+
+type Continue struct {
+	Label string
+}
+
+func (c *Continue) String() string {
+	return "continue"
+}
+
+func (c *Continue) Type() Type {
+	return TYPE_CONTINUE
+}
+
+// Range is the sequence Start, Start+Step, ... up to End, which is
+// part of it only when Inclusive, a negative Step count downward
+type Range struct {
+	Start     int64
+	End       int64
+	Step      int64
+	Inclusive bool
+}
+
+func (r *Range) String() string {
+	op := "..<"
+
+	if r.Inclusive {
+		op = ".."
+	}
+
+	if r.Step != 1 {
+		return fmt.Sprintf("%d%s%d step %d", r.Start, op, r.End, r.Step)
+	}
+
+	return fmt.Sprintf("%d%s%d", r.Start, op, r.End)
+}
+
+func (r *Range) Type() Type {
+	return TYPE_RANGE
+}
+
+// Contains report whether i is one of the values the range produce
+func (r *Range) Contains(i int64) bool {
+	if r.Step > 0 && (i < r.Start || i > r.End || (i == r.End && !r.Inclusive)) {
+		return false
+	}
+
+	if r.Step < 0 && (i > r.Start || i < r.End || (i == r.End && !r.Inclusive)) {
+		return false
+	}
+
+	return (i-r.Start)%r.Step == 0
+}
+
 type Module struct {
 	Value Value
 }
@@ -190,7 +390,8 @@ func (m *Module) Type() Type {
 }
 
 type Array struct {
-	Value []Value
+	Value  []Value
+	Frozen bool // set by Freeze, the elements can't be changed
 }
 
 func (a *Array) String() string {
@@ -216,6 +417,11 @@ func (wf WrapperFunction) Type() Type {
 
 type Error struct {
 	Value string
+	Token token.Token // the token of the node that failed
+}
+
+func (e *Error) Diagnostic() *diagnostic.Diagnostic {
+	return diagnostic.FromToken(e.Token, diagnostic.SEVERITY_ERROR, diagnostic.CODE_RUNTIME, e.Value)
 }
 
 func (e *Error) String() string {